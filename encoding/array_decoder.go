@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/array_decoder.go                                |
 |                                                          |
-| LastModified: Jun 26, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -34,6 +34,8 @@ func (valdec arrayDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 	switch tag {
 	case TagNull, TagEmpty:
 		valdec.at.UnsafeSet(reflect2.PtrOf(p), valdec.empty)
+	case TagRef:
+		dec.decodeReference(p)
 	case TagList:
 		length := valdec.at.Len()
 		count := dec.ReadInt()
@@ -127,10 +129,10 @@ func makeByteArrayDecoder(t reflect.Type) byteArrayDecoder {
 	return byteArrayDecoder{makeArrayDecoder(t, uint8Decode)}
 }
 
-func getArrayDecoder(t reflect.Type) ValueDecoder {
+func (c *Codec) getArrayDecoder(t reflect.Type) ValueDecoder {
 	et := t.Elem()
 	if et.Kind() == reflect.Uint8 {
 		return makeByteArrayDecoder(t)
 	}
-	return makeArrayDecoder(t, GetDecodeHandler(et))
+	return makeArrayDecoder(t, c.GetDecodeHandler(et))
 }
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/bytes_decoder.go                                |
 |                                                          |
-| LastModified: Jun 26, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -14,6 +14,8 @@
 package encoding
 
 import (
+	"fmt"
+	"io"
 	"reflect"
 
 	"github.com/modern-go/reflect2"
@@ -38,6 +40,66 @@ func (dec *Decoder) ReadBytes() []byte {
 	return bytes
 }
 
+// streamedBytes is the reference of the bytes copied by ReadBytesTo, which
+// are not kept by the decoder.
+type streamedBytes struct{}
+
+// ReadBytesTo reads the next bytes value and copies it to w without buffering
+// the whole value, returns the number of bytes copied. The references to the
+// bytes read by ReadBytesTo are decoded as errors.
+func (dec *Decoder) ReadBytesTo(w io.Writer) (written int64, err error) {
+	tag := dec.NextByte()
+	switch tag {
+	case TagNull, TagEmpty:
+		return 0, dec.Error
+	case TagBytes:
+	default:
+		dec.decodeError(bytesType, tag)
+		return 0, dec.Error
+	}
+	remain := int64(dec.ReadInt())
+	if remain < 0 {
+		if dec.Error == nil {
+			dec.Error = DecodeError(fmt.Sprintf("hprose/encoding: invalid bytes length %d", remain))
+		}
+		return 0, dec.Error
+	}
+	dec.AddReference(streamedBytes{})
+	for remain > 0 && err == nil {
+		if dec.head == dec.tail {
+			if dec.reader == nil {
+				dec.loadMore()
+				break
+			}
+			var n int64
+			n, err = io.CopyN(w, dec.reader, remain)
+			written += n
+			remain -= n
+			break
+		}
+		n := int64(dec.tail - dec.head)
+		if n > remain {
+			n = remain
+		}
+		var m int
+		m, err = w.Write(dec.buf[dec.head : dec.head+int(n)])
+		dec.head += m
+		written += int64(m)
+		remain -= int64(m)
+	}
+	if err == io.EOF || err == nil && remain > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		if dec.Error == nil || dec.Error == io.EOF {
+			dec.Error = err
+		}
+		return written, dec.Error
+	}
+	dec.Skip()
+	return written, dec.Error
+}
+
 func (dec *Decoder) readUint8Slice(et reflect.Type) []byte {
 	count := dec.ReadInt()
 	slice := make([]byte, count)
@@ -69,6 +131,8 @@ func (dec *Decoder) decodeBytes(t reflect.Type, tag byte) []byte {
 	case TagGUID:
 		bytes, _ := dec.ReadUUID().MarshalBinary()
 		return bytes
+	case TagRef:
+		return readReference[[]byte](dec)
 	default:
 		dec.decodeError(t, tag)
 	}
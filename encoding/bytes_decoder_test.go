@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/bytes_decoder_test.go                           |
 |                                                          |
-| LastModified: Jun 15, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -14,6 +14,7 @@
 package encoding
 
 import (
+	"io"
 	"strings"
 	"testing"
 
@@ -93,3 +94,51 @@ func TestDecodeBytesPtr(t *testing.T) {
 	dec.Decode(&b)
 	assert.EqualError(t, dec.Error, `hprose/encoding: can not cast int to *[]uint8`) // 1
 }
+
+func TestReadBytesTo(t *testing.T) {
+	data := strings.Repeat("0123456789", 10)
+	sb := new(strings.Builder)
+	enc := NewEncoder(sb).Simple(false)
+	assert.NoError(t, enc.WriteBytesFrom(strings.NewReader(data), 100))
+	assert.NoError(t, enc.Encode([]byte{}))
+	assert.NoError(t, enc.Encode("hello"))
+	assert.Equal(t, `b100"`+data+`"b""s5"hello"`, sb.String())
+	dec := NewDecoderFromReader(strings.NewReader(sb.String()), 32).Simple(false)
+	w := new(strings.Builder)
+	n, err := dec.ReadBytesTo(w)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), n)
+	assert.Equal(t, data, w.String())
+	w.Reset()
+	n, err = dec.ReadBytesTo(w)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	var s string
+	dec.Decode(&s)
+	assert.Equal(t, "hello", s)
+
+	dec = NewDecoder([]byte(`b5"12`))
+	n, err = dec.ReadBytesTo(w)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.Equal(t, int64(2), n)
+	dec = NewDecoder([]byte(`i1;`))
+	_, err = dec.ReadBytesTo(w)
+	assert.Error(t, err)
+	dec = NewDecoder([]byte(`b-5"12345"`))
+	_, err = dec.ReadBytesTo(w)
+	assert.EqualError(t, err, "hprose/encoding: invalid bytes length -5")
+
+	dec = NewDecoder([]byte(`b5"12345"r0;`)).Simple(false)
+	_, err = dec.ReadBytesTo(w)
+	assert.NoError(t, err)
+	var b []byte
+	dec.Decode(&b)
+	assert.EqualError(t, dec.Error, "hprose/encoding: reference 0 to the bytes read by ReadBytesTo")
+}
+
+func TestWriteBytesFrom(t *testing.T) {
+	enc := NewEncoder(nil)
+	assert.NoError(t, enc.WriteBytesFrom(strings.NewReader("hello"), 5))
+	assert.Equal(t, `b5"hello"`, enc.String())
+	assert.Equal(t, io.ErrUnexpectedEOF, enc.WriteBytesFrom(strings.NewReader("hello"), 6))
+}
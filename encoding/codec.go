@@ -0,0 +1,211 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/codec.go                                        |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"io"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/modern-go/reflect2"
+)
+
+// Config is the configuration of a Codec.
+type Config struct {
+	// Tags are the struct tags of the field aliases and options, which are
+	// looked up after the hprose and json tags.
+	Tags []string
+	// NameStrategy names the unregistered struct types, the default is
+	// ShortName.
+	NameStrategy NameStrategy
+	// AutoRegister registers a struct type by its name the first time it
+	// is encoded.
+	AutoRegister bool
+	// FieldNaming names the fields without alias tag, the default is
+	// LowerFirst.
+	FieldNaming FieldNaming
+	// CaseInsensitive matches the fields case-insensitively when decoding.
+	CaseInsensitive bool
+	// LongType, RealType, MapType and ObjectType are the default types of
+	// the Decoders created by the Codec.
+	LongType   LongType
+	RealType   RealType
+	MapType    MapType
+	ObjectType ObjectType
+}
+
+// Codec owns the type registry, the value encoders and decoders, and the
+// struct field tables built by its Config, so that the libraries in the same
+// binary can encode the same types differently. The Encoders and Decoders
+// created by a Codec use it instead of DefaultCodec.
+//
+// The value encoders and decoders registered to a Codec override the ones
+// registered to DefaultCodec, the built-in fast paths are not used for the
+// values encoded or decoded by a Codec which has its own ones. The interface
+// encoders and decoders are used only if no value encoder or decoder is
+// registered for the concrete type itself.
+type Codec struct {
+	config          Config
+	registry        *TypeRegistry
+	custom          atomic.Bool
+	encoders        sync.Map
+	decoders        sync.Map
+	structEncoders  sync.Map
+	valueDecoders   sync.Map
+	fieldOptions    sync.Map
+	fieldMaps       sync.Map
+	interfaces      interfaceCodecs
+	implementations sync.Map
+	fieldCodecs     sync.Map
+}
+
+// Froze returns a new Codec with the configuration.
+func (cfg Config) Froze() *Codec {
+	cfg.Tags = append([]string(nil), cfg.Tags...)
+	c := &Codec{config: cfg}
+	c.registry = NewTypeRegistry(cfg.NameStrategy, cfg.AutoRegister).FieldNaming(cfg.FieldNaming).CaseInsensitive(cfg.CaseInsensitive)
+	c.registry.codec = c
+	return c
+}
+
+// DefaultCodec owns the global registries, it is used by the Encoders and
+// Decoders which are not created by a Codec.
+var DefaultCodec = newDefaultCodec()
+
+func newDefaultCodec() *Codec {
+	c := &Codec{registry: DefaultTypeRegistry}
+	DefaultTypeRegistry.codec = c
+	return c
+}
+
+// Registry returns the type registry of the codec.
+func (c *Codec) Registry() *TypeRegistry {
+	return c.registry
+}
+
+// Register the type of the proto with alias & tag to the codec.
+func (c *Codec) Register(proto interface{}, alias string, tag ...string) error {
+	return c.registry.Register(proto, alias, tag...)
+}
+
+// RegisterWithNaming registers the type of the proto with alias & tag to the
+// codec, the names of its fields without alias tag are given by naming.
+func (c *Codec) RegisterWithNaming(proto interface{}, alias string, naming FieldNaming, tag ...string) error {
+	return c.registry.RegisterWithNaming(proto, alias, naming, tag...)
+}
+
+// RegisterValueEncoder of type(v) to the codec.
+func (c *Codec) RegisterValueEncoder(v interface{}, valenc ValueEncoder) {
+	c.encoders.Store(checkType(v), valenc)
+	if c != DefaultCodec {
+		c.custom.Store(true)
+	}
+}
+
+// RegisterValueDecoder valdec to the codec.
+func (c *Codec) RegisterValueDecoder(valdec ValueDecoder) {
+	c.decoders.Store(valdec.Type(), valdec)
+	if c != DefaultCodec {
+		c.custom.Store(true)
+	}
+}
+
+// isCustom reports whether the codec has its own value encoders or decoders.
+func (c *Codec) isCustom() bool {
+	return c != DefaultCodec && c.custom.Load()
+}
+
+// NewEncoder creates an encoder object which uses the codec.
+func (c *Codec) NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{Writer: w, codec: c}
+}
+
+// NewDecoder creates an Decoder instance from byte array which uses the codec.
+func (c *Codec) NewDecoder(input []byte) *Decoder {
+	return c.setup(NewDecoder(input))
+}
+
+// NewDecoderFromReader creates an Decoder instance from io.Reader which uses
+// the codec.
+func (c *Codec) NewDecoderFromReader(reader io.Reader, bufSize int) *Decoder {
+	return c.setup(NewDecoderFromReader(reader, bufSize))
+}
+
+func (c *Codec) setup(dec *Decoder) *Decoder {
+	dec.codec = c
+	dec.LongType = c.config.LongType
+	dec.RealType = c.config.RealType
+	dec.MapType = c.config.MapType
+	dec.ObjectType = c.config.ObjectType
+	return dec
+}
+
+// Append appends the hprose encoding of v in simple mode to dst and returns
+// the extended buffer.
+func (c *Codec) Append(dst []byte, v interface{}) ([]byte, error) {
+	enc := c.NewEncoder(nil)
+	enc.ResetBuffer(dst)
+	err := enc.Encode(v)
+	return enc.buf, err
+}
+
+// ownDecoder returns the value decoder of type t registered to the codec
+// itself, it is only used when the codec is custom.
+func (c *Codec) ownDecoder(t reflect.Type) ValueDecoder {
+	if valdec, ok := c.decoders.Load(t); ok {
+		return valdec.(ValueDecoder)
+	}
+	return nil
+}
+
+// ownEncoder returns the value encoder of type t registered to the codec
+// itself, it is only used when the codec is custom.
+func (c *Codec) ownEncoder(t reflect.Type) ValueEncoder {
+	if valenc, ok := c.encoders.Load(t); ok {
+		return valenc.(ValueEncoder)
+	}
+	return nil
+}
+
+// isFastDecoder reports whether the decoder of t is a built-in fast decoder
+// of slices or maps, which is not used by the custom codecs.
+func isFastDecoder(t reflect.Type) bool {
+	rt := reflect2.Type2(reflect.PtrTo(t)).RType()
+	return sliceDecoders[rt] != nil || mapDecoders[rt] != nil
+}
+
+// getCodec returns the codec which creates enc, or the codec of its
+// Registry, or DefaultCodec.
+func (enc *Encoder) getCodec() *Codec {
+	switch {
+	case enc.codec != nil:
+		return enc.codec
+	case enc.Registry != nil:
+		return enc.Registry.codec
+	}
+	return DefaultCodec
+}
+
+// getCodec returns the codec which creates dec, or the codec of its
+// Registry, or DefaultCodec.
+func (dec *Decoder) getCodec() *Codec {
+	switch {
+	case dec.codec != nil:
+		return dec.codec
+	case dec.Registry != nil:
+		return dec.Registry.codec
+	}
+	return DefaultCodec
+}
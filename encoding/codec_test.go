@@ -0,0 +1,140 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/codec_test.go                                   |
+|                                                          |
+| LastModified: Oct 18, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type unixTimeEncoder struct{}
+
+func (unixTimeEncoder) Encode(enc *Encoder, v interface{}) {
+	unixTimeEncoder{}.Write(enc, v)
+}
+
+func (unixTimeEncoder) Write(enc *Encoder, v interface{}) {
+	switch v := v.(type) {
+	case time.Time:
+		enc.WriteInt64(v.Unix())
+	case *time.Time:
+		enc.WriteInt64(v.Unix())
+	}
+}
+
+type unixTimeDecoder struct{}
+
+func (unixTimeDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	var sec int64
+	dec.decode(&sec, tag)
+	*p.(*time.Time) = time.Unix(sec, 0)
+}
+
+func (unixTimeDecoder) Type() reflect.Type {
+	return timeType
+}
+
+type TestCodecStruct struct {
+	CreatedAt time.Time
+	UserName  string `xml:"user"`
+	Times     []time.Time
+}
+
+func TestCodecValueEncoders(t *testing.T) {
+	codec := Config{}.Froze()
+	codec.RegisterValueEncoder(time.Time{}, unixTimeEncoder{})
+	codec.RegisterValueDecoder(unixTimeDecoder{})
+	ts := time.Unix(1600000000, 0)
+
+	data, err := codec.Append(nil, ts)
+	assert.NoError(t, err)
+	assert.Equal(t, "i1600000000;", string(data))
+	data, err = Append(nil, ts)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "i1600000000;", string(data))
+
+	data, err = codec.Append(nil, []time.Time{ts})
+	assert.NoError(t, err)
+	assert.Equal(t, "a1{i1600000000;}", string(data))
+
+	var v TestCodecStruct
+	dec := codec.NewDecoder([]byte(`m2{s9"createdAt"i1600000000;s5"times"a1{i1600000000;}}`))
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.True(t, ts.Equal(v.CreatedAt))
+	assert.Len(t, v.Times, 1)
+	assert.True(t, ts.Equal(v.Times[0]))
+
+	var times []time.Time
+	dec = codec.NewDecoder([]byte(`a1{i1600000000;}`))
+	dec.Decode(&times)
+	assert.NoError(t, dec.Error)
+	assert.Len(t, times, 1)
+	assert.True(t, ts.Equal(times[0]))
+
+	dec = NewDecoder([]byte(`a1{i1600000000;}`))
+	dec.Decode(&times)
+	assert.NoError(t, dec.Error)
+	assert.False(t, ts.Equal(times[0]))
+}
+
+func TestCodecFieldConfig(t *testing.T) {
+	codec := Config{
+		Tags:        []string{"xml"},
+		FieldNaming: SnakeCase,
+	}.Froze()
+	codec.Register((*TestCodecStruct)(nil), "CodecStruct")
+	v := TestCodecStruct{UserName: "Tom"}
+
+	data, err := codec.Append(nil, v)
+	assert.NoError(t, err)
+	head := `c11"CodecStruct"3{s10"created_at"s4"user"s5"times"}o0{`
+	assert.Equal(t, head, string(data[:len(head)]))
+	data, err = Append(nil, v)
+	assert.NoError(t, err)
+	head = `c15"TestCodecStruct"3{s9"createdAt"s8"userName"s5"times"}o0{`
+	assert.Equal(t, head, string(data[:len(head)]))
+
+	var v2 TestCodecStruct
+	dec := codec.NewDecoder([]byte(`c11"CodecStruct"1{s4"user"}o0{s5"Jerry"}`))
+	dec.Decode(&v2)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, "Jerry", v2.UserName)
+
+	var v3 interface{}
+	dec = codec.NewDecoder([]byte(`c11"CodecStruct"1{s4"user"}o0{s5"Jerry"}`))
+	dec.Decode(&v3)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, &TestCodecStruct{UserName: "Jerry"}, v3)
+	assert.Nil(t, GetStructType("CodecStruct"))
+}
+
+func TestCodecDecoderTypes(t *testing.T) {
+	codec := Config{
+		LongType: LongTypeInt64,
+		MapType:  MapTypeSIMap,
+	}.Froze()
+	var v interface{}
+	dec := codec.NewDecoder([]byte(`l12345678901234567890;`))
+	assert.Equal(t, LongTypeInt64, dec.LongType)
+	assert.Equal(t, MapTypeSIMap, dec.MapType)
+	dec.Decode(&v)
+	assert.IsType(t, int64(0), v)
+	dec = codec.NewDecoder([]byte(`m1{s1"a"1}`))
+	dec.Decode(&v)
+	assert.Equal(t, map[string]interface{}{"a": 1}, v)
+}
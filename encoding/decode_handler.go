@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/decode_handler.go                               |
 |                                                          |
-| LastModified: Jun 27, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -19,6 +19,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/google/uuid"
 	"github.com/modern-go/reflect2"
 )
 
@@ -109,6 +110,10 @@ func timeDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
 	*(*time.Time)(p) = dec.decodeTime(t, dec.NextByte())
 }
 
+func uuidDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+	*(*uuid.UUID)(p) = dec.decodeUUID(t, dec.NextByte())
+}
+
 func bigIntDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
 	*(**big.Int)(p) = dec.decodeBigInt(t, dec.NextByte())
 }
@@ -193,8 +198,8 @@ func stringPtrDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
 	*(**string)(p) = dec.decodeStringPtr(t, dec.NextByte())
 }
 
-func otherDecode(t reflect.Type) DecodeHandler {
-	valdec := GetValueDecoder(t)
+func otherDecode(c *Codec, t reflect.Type) DecodeHandler {
+	valdec := c.GetValueDecoder(t)
 	t2 := reflect2.Type2(t)
 	return func(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
 		valdec.Decode(dec, t2.PackEFace(p), dec.NextByte())
@@ -203,18 +208,35 @@ func otherDecode(t reflect.Type) DecodeHandler {
 
 // GetDecodeHandler for specified type
 func GetDecodeHandler(t reflect.Type) DecodeHandler {
-	if getValueDecoder(t) == nil {
+	return DefaultCodec.GetDecodeHandler(t)
+}
+
+// GetDecodeHandler for specified type in the codec.
+func (c *Codec) GetDecodeHandler(t reflect.Type) DecodeHandler {
+	if c.getValueDecoder(t) == nil {
 		kind := t.Kind()
-		if decode := decodeHandlers[kind]; decode != nil {
+		if decode := decodeHandlers[kind]; decode != nil && !isImplementation(t) {
 			return decode
 		}
-		if kind == reflect.Ptr {
-			if decode := decodePtrHandlers[t.Elem().Kind()]; decode != nil {
+		if kind == reflect.Ptr && !c.overridesDecoder(t.Elem()) {
+			if decode := decodePtrHandlers[t.Elem().Kind()]; decode != nil && !isImplementation(t.Elem()) {
 				return decode
 			}
 		}
 	}
-	return otherDecode(t)
+	return otherDecode(c, t)
+}
+
+// overridesDecoder reports whether the built-in decoder of t is overridden by
+// a value decoder of the codec itself, or by an interface decoder.
+func (c *Codec) overridesDecoder(t reflect.Type) bool {
+	return (c.isCustom() && c.ownDecoder(t) != nil) || c.interfaceDecoder(t) != nil
+}
+
+// isImplementation reports whether t is a non-empty interface, which is
+// decoded into its implementations.
+func isImplementation(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.NumMethod() > 0
 }
 
 var (
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/decoder.go                                      |
 |                                                          |
-| LastModified: Jun 25, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -58,21 +58,44 @@ const (
 	MapTypeSIMap
 )
 
+// ObjectType represents the default type for decode object of unregistered struct
+type ObjectType int8
+
+const (
+	// ObjectTypeMap represents the default type is map[string]interface{}
+	ObjectTypeMap ObjectType = iota
+	// ObjectTypeObject represents the default type is *Object
+	ObjectTypeObject
+)
+
 const defaultBufferSize = 8192
 
 // Decoder is a io.Reader like object, with hprose specific read functions.
 // Error is not returned as return value, but stored as Error member on this decoder instance.
 type Decoder struct {
-	reader io.Reader
-	buf    []byte
-	head   int
-	tail   int
-	refer  *decoderRefer
-	ref    []structInfo
-	Error  error
+	reader   io.Reader
+	buf      []byte
+	head     int
+	tail     int
+	refer    *decoderRefer
+	ref      []structInfo
+	record   *decoderRecord
+	session  bool
+	exact    bool
+	Error    error
+	Registry *TypeRegistry
+	codec    *Codec
 	LongType
 	RealType
 	MapType
+	ObjectType
+}
+
+func (dec *Decoder) getRegistry() *TypeRegistry {
+	if dec.Registry != nil {
+		return dec.Registry
+	}
+	return dec.getCodec().registry
 }
 
 // NewDecoder creates an Decoder instance from byte array
@@ -213,34 +236,50 @@ func (dec *Decoder) fastDecodePtr(p interface{}, tag byte) bool {
 func (dec *Decoder) decode(p interface{}, tag byte) {
 	switch tag {
 	case TagRef:
-
+		dec.decodeReference(p)
+		return
 	case TagClass:
 		dec.ReadStruct()
 		dec.Decode(p)
 		return
 	case TagError:
-		dec.Error = DecodeError(dec.decodeString(stringType, dec.NextByte()))
+		switch p := p.(type) {
+		case *Value:
+			dec.readValue(p, tag)
+		case **Value:
+			*p = new(Value)
+			dec.readValue(*p, tag)
+		default:
+			dec.Error = DecodeError(dec.decodeString(stringType, dec.NextByte()))
+		}
 		return
 	}
+	c := dec.getCodec()
+	if c.isCustom() {
+		if valdec := c.ownDecoder(reflect.TypeOf(p).Elem()); valdec != nil {
+			valdec.Decode(dec, p, tag)
+			return
+		}
+	}
 	if dec.fastDecode(p, tag) {
 		return
 	}
 	t := reflect.TypeOf(p).Elem()
 	switch t.Kind() {
 	case reflect.Map:
-		if dec.fastDecodeMap(t, p, tag) {
+		if !c.isCustom() && dec.fastDecodeMap(p, tag) {
 			return
 		}
 	case reflect.Ptr:
-		if dec.fastDecodePtr(p, tag) {
+		if !c.isCustom() && dec.fastDecodePtr(p, tag) {
 			return
 		}
 	case reflect.Slice:
-		if dec.fastDecodeSlice(p, tag) {
+		if !c.isCustom() && dec.fastDecodeSlice(p, tag) {
 			return
 		}
 	}
-	if valdec := GetValueDecoder(t); valdec != nil {
+	if valdec := c.GetValueDecoder(t); valdec != nil {
 		valdec.Decode(dec, p, tag)
 	}
 }
@@ -250,15 +289,97 @@ func (dec *Decoder) Decode(p interface{}) {
 	dec.decode(p, dec.NextByte())
 }
 
-// Reset the value reference and struct type reference
+// Reset the value reference and struct type reference,
+// the struct type reference is kept in session mode.
 func (dec *Decoder) Reset() *Decoder {
 	if !dec.IsSimple() {
 		dec.refer.Reset()
 	}
-	dec.ref = dec.ref[:0]
+	if !dec.session {
+		dec.ref = dec.ref[:0]
+	}
+	return dec
+}
+
+// Session sets the decoder to session mode or not.
+// In session mode, the struct types read from stream are remembered across Reset,
+// it should be used with an encoder in session mode.
+func (dec *Decoder) Session(session bool) *Decoder {
+	dec.session = session
+	if !session {
+		dec.ref = dec.ref[:0]
+	}
 	return dec
 }
 
+// IsSession returns the decoder is in session mode or not
+func (dec *Decoder) IsSession() bool {
+	return dec.session
+}
+
+// More reports whether there is another value in the stream. It returns
+// false at the end of stream, or if an error has occurred.
+func (dec *Decoder) More() bool {
+	if dec.head < dec.tail {
+		return true
+	}
+	if dec.Error != nil {
+		return false
+	}
+	if dec.loadMore() {
+		return true
+	}
+	if dec.Error == io.EOF {
+		dec.Error = nil
+	}
+	return false
+}
+
+// DecodeNext decodes the next value in the stream to p, and reports whether
+// a value is decoded without error, so the values in a stream can be decoded
+// by:
+//
+//	for dec.DecodeNext(&v) {
+//		// use v
+//	}
+//	if dec.Error != nil {
+//		// handle error
+//	}
+//
+// dec.Error is nil at the end of stream, and io.ErrUnexpectedEOF if the stream
+// ends in the middle of a value.
+func (dec *Decoder) DecodeNext(p interface{}) bool {
+	if !dec.More() {
+		return false
+	}
+	dec.Decode(p)
+	if dec.Error == io.EOF {
+		dec.Error = io.ErrUnexpectedEOF
+	}
+	return dec.Error == nil
+}
+
+// Exact sets the decoder to exact mode or not. In exact mode, the decoder
+// never reads ahead from the reader, so the reader is left positioned right
+// after the last decoded value. The bytes are read one by one, via ReadByte if
+// the reader is an io.ByteReader.
+func (dec *Decoder) Exact(exact bool) *Decoder {
+	dec.exact = exact
+	return dec
+}
+
+// IsExact returns the decoder is in exact mode or not
+func (dec *Decoder) IsExact() bool {
+	return dec.exact
+}
+
+// Buffered returns a reader of the data remaining in the decoder's buffer,
+// which has been read from the reader but not been decoded yet.
+// The reader is valid until the next call to a read method.
+func (dec *Decoder) Buffered() io.Reader {
+	return bytes.NewReader(dec.buf[dec.head:dec.tail])
+}
+
 // Simple resets the decoder to simple mode or not
 func (dec *Decoder) Simple(simple bool) *Decoder {
 	if simple {
@@ -292,7 +413,7 @@ func (dec *Decoder) SetReference(i int, o interface{}) {
 // LastReferenceIndex returns the last index of the reference
 func (dec *Decoder) LastReferenceIndex() int {
 	if !dec.IsSimple() {
-		dec.refer.Last()
+		return dec.refer.Last()
 	}
 	return -1
 }
@@ -436,6 +557,21 @@ func (dec *Decoder) Until(delim byte) []byte {
 	return append(([]byte)(nil), data...)
 }
 
+func (dec *Decoder) read() (n int, err error) {
+	if !dec.exact {
+		return dec.reader.Read(dec.buf)
+	}
+	if br, ok := dec.reader.(io.ByteReader); ok {
+		var b byte
+		if b, err = br.ReadByte(); err == nil {
+			dec.buf[0] = b
+			n = 1
+		}
+		return
+	}
+	return dec.reader.Read(dec.buf[:1])
+}
+
 func (dec *Decoder) loadMore() bool {
 	if dec.reader == nil {
 		dec.head = dec.tail
@@ -443,11 +579,15 @@ func (dec *Decoder) loadMore() bool {
 			dec.Error = io.EOF
 		}
 		return false
-	} else if dec.buf == nil {
+	} else if len(dec.buf) == 0 {
 		dec.buf = make([]byte, defaultBufferSize)
 	}
+	for r := dec.record; r != nil; r = r.prev {
+		r.data = append(r.data, dec.buf[r.head:dec.tail]...)
+		r.head, r.copied = 0, true
+	}
 	for {
-		n, err := dec.reader.Read(dec.buf)
+		n, err := dec.read()
 		dec.head = 0
 		dec.tail = n
 		if n > 0 {
@@ -6,19 +6,38 @@
 |                                                          |
 | encoding/decoder_refer.go                                |
 |                                                          |
-| LastModified: Apr 19, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
 
 package encoding
 
+import (
+	"fmt"
+	"reflect"
+)
+
+// decoderRefer holds the values which can be referenced. The structs and
+// arrays are held by the typed pointers to them, so that the references to
+// them rebuild the shared and cyclic graphs, the slices and maps are held by
+// themselves, since they may be decoded into the temporary variables.
 type decoderRefer struct {
 	ref []interface{}
 }
 
+func referenceable(o interface{}) interface{} {
+	if t := reflect.TypeOf(o); t != nil && t.Kind() == reflect.Ptr {
+		switch t.Elem().Kind() {
+		case reflect.Slice, reflect.Map:
+			return reflect.ValueOf(o).Elem().Interface()
+		}
+	}
+	return o
+}
+
 func (r *decoderRefer) Add(o interface{}) {
-	r.ref = append(r.ref, o)
+	r.ref = append(r.ref, referenceable(o))
 }
 
 func (r *decoderRefer) Last() int {
@@ -26,7 +45,7 @@ func (r *decoderRefer) Last() int {
 }
 
 func (r *decoderRefer) Set(i int, o interface{}) {
-	r.ref[i] = o
+	r.ref[i] = referenceable(o)
 }
 
 func (r *decoderRefer) Read(i int) interface{} {
@@ -36,3 +55,66 @@ func (r *decoderRefer) Read(i int) interface{} {
 func (r *decoderRefer) Reset() {
 	r.ref = r.ref[:0]
 }
+
+// ReadReference reads the index of a reference and returns the referenced
+// value, the structs and arrays are returned as pointers to them.
+func (dec *Decoder) ReadReference() interface{} {
+	i := dec.ReadInt()
+	switch {
+	case dec.IsSimple():
+		if dec.Error == nil {
+			dec.Error = DecodeError("hprose/encoding: unexpected reference in simple mode")
+		}
+	case i < 0 || i >= len(dec.refer.ref):
+		if dec.Error == nil {
+			dec.Error = DecodeError(fmt.Sprintf("hprose/encoding: invalid reference index %d", i))
+		}
+	default:
+		ref := dec.refer.Read(i)
+		if _, ok := ref.(streamedBytes); !ok {
+			return ref
+		}
+		if dec.Error == nil {
+			dec.Error = DecodeError(fmt.Sprintf("hprose/encoding: reference %d to the bytes read by ReadBytesTo", i))
+		}
+	}
+	return nil
+}
+
+// readReference reads a reference to a value of type T.
+func readReference[T any](dec *Decoder) (v T) {
+	dec.decodeReference(&v)
+	return
+}
+
+// decodeReference reads a reference and sets the referenced value to the
+// variable p points to. A pointer variable is set to the referenced pointer,
+// so it shares the referenced value.
+func (dec *Decoder) decodeReference(p interface{}) {
+	ref := dec.ReadReference()
+	dst := reflect.ValueOf(p).Elem()
+	if t := dst.Type(); t == valueType || t == valuePtrType {
+		ref = valueOfReference(ref)
+	} else if v, ok := ref.(*Value); ok {
+		ref = v.referenced()
+	}
+	if ref == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return
+	}
+	v := reflect.ValueOf(ref)
+	switch t := dst.Type(); {
+	case v.Type().AssignableTo(t):
+		dst.Set(v)
+	case v.Kind() == reflect.Ptr && v.Type().Elem().AssignableTo(t):
+		dst.Set(v.Elem())
+	case t.Kind() == reflect.Ptr && v.Type().AssignableTo(t.Elem()):
+		e := reflect.New(t.Elem())
+		e.Elem().Set(v)
+		dst.Set(e)
+	default:
+		if dec.Error == nil {
+			dec.Error = CastError{v.Type(), t}
+		}
+	}
+}
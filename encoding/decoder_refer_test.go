@@ -0,0 +1,138 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/decoder_refer_test.go                           |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeWithReference(t *testing.T, v interface{}) []byte {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	assert.NoError(t, enc.Encode(v))
+	return []byte(sb.String())
+}
+
+func TestDecodeSharedPointers(t *testing.T) {
+	a := &TestNode{Name: "a"}
+	data := encodeWithReference(t, []*TestNode{a, a, {Name: "b"}})
+	var nodes []*TestNode
+	dec := NewDecoder(data).Simple(false)
+	dec.Decode(&nodes)
+	assert.NoError(t, dec.Error)
+	assert.Len(t, nodes, 3)
+	assert.Equal(t, "a", nodes[0].Name)
+	assert.Same(t, nodes[0], nodes[1])
+	assert.False(t, nodes[0] == nodes[2])
+
+	var values []TestNode
+	dec = NewDecoder(data).Simple(false)
+	dec.Decode(&values)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []TestNode{{Name: "a"}, {Name: "a"}, {Name: "b"}}, values)
+}
+
+func TestDecodeCyclicGraph(t *testing.T) {
+	data := encodeWithReference(t, newTestTree())
+	var root *TestNode
+	dec := NewDecoder(data).Simple(false)
+	dec.Decode(&root)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, "root", root.Name)
+	assert.Len(t, root.Children, 1)
+	assert.Equal(t, "a", root.Children[0].Name)
+	assert.Same(t, root, root.Children[0].Parent)
+}
+
+func TestDecodeSharedSlicesAndMaps(t *testing.T) {
+	s := []int{1, 2}
+	var slices [][]int
+	dec := NewDecoder(encodeWithReference(t, []*[]int{&s, &s})).Simple(false)
+	dec.Decode(&slices)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, [][]int{{1, 2}, {1, 2}}, slices)
+	slices[0][0] = 3
+	assert.Equal(t, 3, slices[1][0])
+
+	m := map[string]int{"x": 1}
+	var maps []map[string]int
+	dec = NewDecoder(encodeWithReference(t, []interface{}{&m, &m})).Simple(false)
+	dec.Decode(&maps)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []map[string]int{{"x": 1}, {"x": 1}}, maps)
+	maps[0]["y"] = 2
+	assert.Equal(t, 2, maps[1]["y"])
+
+	var v interface{}
+	dec = NewDecoder(encodeWithReference(t, []interface{}{&m, &m})).Simple(false)
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	list := v.([]interface{})
+	assert.Equal(t, reflect.ValueOf(list[0]).Pointer(), reflect.ValueOf(list[1]).Pointer())
+}
+
+func TestDecodeReferencedMapValues(t *testing.T) {
+	a, b := &TestNode{Name: "a"}, &TestNode{Name: "b"}
+	data := encodeWithReference(t, []interface{}{a, b, a})
+	var m map[int]TestNode
+	dec := NewDecoder(data).Simple(false)
+	dec.Decode(&m)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, map[int]TestNode{0: {Name: "a"}, 1: {Name: "b"}, 2: {Name: "a"}}, m)
+}
+
+func TestDecodeReferencedValues(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	id := uuid.New()
+	data := encodeWithReference(t, []interface{}{"hello", "hello", now, now, id, id, []byte("hi"), []byte("hi")})
+	var list []interface{}
+	dec := NewDecoder(data).Simple(false)
+	dec.Decode(&list)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []interface{}{"hello", "hello", now, now, id, id, []byte("hi"), []byte("hi")}, list)
+
+	var strs []string
+	dec = NewDecoder(encodeWithReference(t, []string{"hello", "hello"})).Simple(false)
+	dec.Decode(&strs)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []string{"hello", "hello"}, strs)
+
+	var times []*time.Time
+	dec = NewDecoder(encodeWithReference(t, []time.Time{now, now})).Simple(false)
+	dec.Decode(&times)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, now, *times[1])
+}
+
+func TestDecodeReferenceErrors(t *testing.T) {
+	var nodes []*TestNode
+	dec := NewDecoder([]byte(`a2{c8"TestNode"3{s4"name"s6"parent"s8"children"}o0{uann}r1;}`))
+	dec.Decode(&nodes)
+	assert.EqualError(t, dec.Error, "hprose/encoding: unexpected reference in simple mode")
+
+	dec = NewDecoder([]byte(`a1{r5;}`)).Simple(false)
+	dec.Decode(&nodes)
+	assert.EqualError(t, dec.Error, "hprose/encoding: invalid reference index 5")
+
+	var ints []int
+	dec = NewDecoder([]byte(`a2{1r0;}`)).Simple(false)
+	dec.Decode(&ints)
+	assert.Equal(t, CastError{reflect.TypeOf([]int(nil)), reflect.TypeOf(0)}, dec.Error)
+}
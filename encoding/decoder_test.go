@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/decoder_test.go                                 |
 |                                                          |
-| LastModified: Apr 25, 2020                               |
+| LastModified: Oct 18, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -16,6 +16,7 @@ package encoding
 import (
 	"bytes"
 	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
 	"time"
@@ -202,6 +203,71 @@ func TestRemains(t *testing.T) {
 	assert.EqualError(t, dec.Error, io.EOF.Error())
 }
 
+func TestExact(t *testing.T) {
+	data := `s5"hello"a2{12}i123;REST`
+	reader := strings.NewReader(data)
+	dec := NewDecoderFromReader(reader, 32).Exact(true)
+	assert.True(t, dec.IsExact())
+	var s string
+	var a []int
+	var i int
+	dec.Decode(&s)
+	dec.Decode(&a)
+	dec.Decode(&i)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, "hello", s)
+	assert.Equal(t, []int{1, 2}, a)
+	assert.Equal(t, 123, i)
+	rest, _ := ioutil.ReadAll(reader)
+	assert.Equal(t, "REST", string(rest))
+
+	r := struct{ io.Reader }{strings.NewReader(data)}
+	dec = NewDecoderFromReader(r, 32).Exact(true)
+	dec.Decode(&s)
+	rest, _ = ioutil.ReadAll(r)
+	assert.Equal(t, `a2{12}i123;REST`, string(rest))
+}
+
+func TestBuffered(t *testing.T) {
+	reader := strings.NewReader(`s5"hello"REST`)
+	dec := NewDecoderFromReader(reader, 32)
+	var s string
+	dec.Decode(&s)
+	assert.Equal(t, "hello", s)
+	rest, _ := ioutil.ReadAll(io.MultiReader(dec.Buffered(), reader))
+	assert.Equal(t, "REST", string(rest))
+}
+
+func TestDecodeNext(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	for i := 0; i < 3; i++ {
+		enc.Encode([]int{i, i + 1})
+		enc.Reset()
+	}
+	dec := NewDecoderFromReader(strings.NewReader(sb.String()), 32)
+	var result [][]int
+	var v []int
+	for dec.DecodeNext(&v) {
+		result = append(result, v)
+		v = nil
+	}
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, [][]int{{0, 1}, {1, 2}, {2, 3}}, result)
+	assert.False(t, dec.More())
+
+	dec = NewDecoder([]byte(`a2{12}a2{1`))
+	assert.True(t, dec.DecodeNext(&v))
+	assert.True(t, dec.More())
+	assert.False(t, dec.DecodeNext(&v))
+	assert.Equal(t, io.ErrUnexpectedEOF, dec.Error)
+	assert.False(t, dec.More())
+
+	dec = NewDecoder(nil)
+	assert.False(t, dec.More())
+	assert.NoError(t, dec.Error)
+}
+
 func TestUntil(t *testing.T) {
 	data := ([]byte)(";1;12;123;1234;12345;123456;1234567;12345678;123456789;1234567890")
 	dec := NewDecoderFromReader(bytes.NewBuffer(data), 32)
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/encode_handler.go                               |
 |                                                          |
-| LastModified: Apr 12, 2020                               |
+| LastModified: Oct 18, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -23,8 +23,13 @@ import (
 type EncodeHandler func(enc *Encoder, v interface{})
 
 // GetEncodeHandler for specified type
-func GetEncodeHandler(t reflect.Type) (handler EncodeHandler) {
-	if handler = getOtherEncodeHandler(t); handler == nil {
+func GetEncodeHandler(t reflect.Type) EncodeHandler {
+	return DefaultCodec.GetEncodeHandler(t)
+}
+
+// GetEncodeHandler for specified type in the codec.
+func (c *Codec) GetEncodeHandler(t reflect.Type) (handler EncodeHandler) {
+	if handler = c.getOtherEncodeHandler(t); handler == nil {
 		switch t.Kind() {
 		case reflect.Int:
 			handler = intEncode
@@ -63,13 +68,13 @@ func GetEncodeHandler(t reflect.Type) (handler EncodeHandler) {
 		case reflect.Map:
 			handler = mapEncode
 		case reflect.Ptr:
-			handler = getPtrEncodeHandler(t.Elem())
+			handler = c.getPtrEncodeHandler(t.Elem())
 		case reflect.Slice:
 			handler = sliceEncode
 		case reflect.String:
 			handler = stringEncode
 		case reflect.Struct:
-			handler = getStructEncodeHandler(t)
+			handler = c.getStructEncodeHandler(t)
 		}
 	}
 	return
@@ -348,30 +353,30 @@ func ptrEncode(enc *Encoder, v interface{}) {
 	}
 }
 
-func getStructEncodeHandler(t reflect.Type) EncodeHandler {
-	return getStructEncoder(t).Write
+func (c *Codec) getStructEncodeHandler(t reflect.Type) EncodeHandler {
+	return c.getStructEncoder(t).Write
 }
 
-func getStructPtrEncodeHandler(t reflect.Type) EncodeHandler {
-	return getStructEncoder(t).Encode
+func (c *Codec) getStructPtrEncodeHandler(t reflect.Type) EncodeHandler {
+	return c.getStructEncoder(t).Encode
 }
 
-func getOtherEncodeHandler(t reflect.Type) (handler EncodeHandler) {
-	if encoder := getOtherEncoder(t); encoder != nil {
+func (c *Codec) getOtherEncodeHandler(t reflect.Type) (handler EncodeHandler) {
+	if encoder := c.getOtherEncoder(t); encoder != nil {
 		handler = encoder.Write
 	}
 	return
 }
 
-func getOtherPtrEncodeHandler(t reflect.Type) (handler EncodeHandler) {
-	if encoder := getOtherEncoder(t); encoder != nil {
+func (c *Codec) getOtherPtrEncodeHandler(t reflect.Type) (handler EncodeHandler) {
+	if encoder := c.getOtherEncoder(t); encoder != nil {
 		handler = encoder.Encode
 	}
 	return
 }
 
-func getPtrEncodeHandler(t reflect.Type) (handler EncodeHandler) {
-	if handler = getOtherPtrEncodeHandler(t); handler == nil {
+func (c *Codec) getPtrEncodeHandler(t reflect.Type) (handler EncodeHandler) {
+	if handler = c.getOtherPtrEncodeHandler(t); handler == nil {
 		switch t.Kind() {
 		case reflect.Int:
 			handler = intPtrEncode
@@ -424,7 +429,7 @@ func getPtrEncodeHandler(t reflect.Type) (handler EncodeHandler) {
 		case reflect.String:
 			handler = stringPtrEncode
 		case reflect.Struct:
-			handler = getStructPtrEncodeHandler(t)
+			handler = c.getStructPtrEncodeHandler(t)
 		}
 	}
 	return handler
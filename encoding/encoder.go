@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/encoder.go                                      |
 |                                                          |
-| LastModified: Apr 12, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -17,21 +17,48 @@ import (
 	"io"
 	"math/big"
 	"reflect"
+	"sync"
 	"unsafe"
 
 	"github.com/modern-go/reflect2"
 )
 
 // An Encoder writes hprose data to an output stream
+//
+// Every hprose value is self-delimiting, so the values written by successive
+// Encode/Write calls can be stored back-to-back in one stream (such as an
+// append-only log file), and read back one by one with Decoder.More and
+// Decoder.DecodeNext. Each call writes one value to Writer in a single Write
+// unless AutoFlush is set. The references and struct types written by a call
+// can be referenced by the following calls, so to make every value decodable
+// on its own, use the encoder in simple mode and call Reset after each value,
+// or use a new encoder for each value.
 type Encoder struct {
-	addr   *Encoder // of receiver, to detect copies by value
-	buf    []byte
-	off    int
-	refer  *encoderRefer
-	ref    map[reflect.Type]int
-	last   int
-	Writer io.Writer
-	Error  error
+	addr        *Encoder // of receiver, to detect copies by value
+	buf         []byte
+	borrowed    bool // buf is passed to ResetBuffer by the caller
+	off         int
+	start       int
+	refer       *encoderRefer
+	ref         map[interface{}]int
+	last        int
+	session     bool
+	threshold   int
+	flushed     int64
+	heads       []int64
+	Writer      io.Writer
+	Error       error
+	Registry    *TypeRegistry
+	codec       *Codec
+	path        encoderPath
+	breakCycles bool
+}
+
+func (enc *Encoder) getRegistry() *TypeRegistry {
+	if enc.Registry != nil {
+		return enc.Registry
+	}
+	return enc.getCodec().registry
 }
 
 // NewEncoder create an encoder object
@@ -39,6 +66,22 @@ func NewEncoder(w io.Writer) *Encoder {
 	return &Encoder{Writer: w}
 }
 
+var encoderPool = sync.Pool{
+	New: func() interface{} { return new(Encoder) },
+}
+
+// Append appends the hprose encoding of v in simple mode to dst and returns
+// the extended buffer.
+func Append(dst []byte, v interface{}) ([]byte, error) {
+	enc := encoderPool.Get().(*Encoder)
+	enc.ResetBuffer(dst)
+	err := enc.Encode(v)
+	dst = enc.buf
+	enc.buf = nil
+	encoderPool.Put(enc)
+	return dst, err
+}
+
 func (enc *Encoder) copyCheck() {
 	if enc.addr == nil {
 		// This hack works around a failing of Go's escape analysis
@@ -104,19 +147,31 @@ func (enc *Encoder) fastWriteValue(v interface{}) (ok bool) {
 }
 
 func (enc *Encoder) writeValue(v interface{}, encode func(m ValueEncoder, v interface{})) {
+	c := enc.getCodec()
+	if c.isCustom() && v != nil {
+		t := reflect.TypeOf(v)
+		if valenc := c.ownEncoder(t); valenc != nil {
+			if t.Kind() == reflect.Struct {
+				valenc.Write(enc, v)
+			} else {
+				encode(valenc, v)
+			}
+			return
+		}
+	}
 	if enc.fastWriteValue(v) {
 		return
 	}
 	t := reflect.TypeOf(v)
 	kind := t.Kind()
 	switch kind {
-	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
 		if reflect.ValueOf(v).IsNil() {
 			enc.WriteNil()
 			return
 		}
 	}
-	if valenc := getOtherEncoder(t); valenc != nil {
+	if valenc := c.getOtherEncoder(t); valenc != nil {
 		encode(valenc, v)
 		return
 	}
@@ -156,13 +211,25 @@ func (enc *Encoder) writeValue(v interface{}, encode func(m ValueEncoder, v inte
 	case reflect.Array:
 		enc.WriteArray(v)
 	case reflect.Struct:
-		getStructEncoder(t).Write(enc, v)
+		c.getStructEncoder(t).Write(enc, v)
 	case reflect.Slice:
 		enc.WriteSlice(v)
 	case reflect.Map:
 		enc.WriteMap(v)
 	case reflect.Ptr:
 		encode(ptrenc, v)
+	case reflect.Chan:
+		if t.ChanDir()&reflect.RecvDir == 0 {
+			enc.Error = UnsupportedTypeError{t}
+			enc.WriteNil()
+			return
+		}
+		enc.writeChan(v)
+	case reflect.Func:
+		if !enc.writeSeq(v) {
+			enc.Error = UnsupportedTypeError{t}
+			enc.WriteNil()
+		}
 	default:
 		enc.Error = UnsupportedTypeError{reflect.TypeOf(v)}
 		enc.WriteNil()
@@ -173,12 +240,39 @@ func (enc *Encoder) encode(v interface{}) {
 	enc.writeValue(v, func(valenc ValueEncoder, v interface{}) {
 		valenc.Encode(enc, v)
 	})
+	enc.autoFlush()
 }
 
 func (enc *Encoder) write(v interface{}) {
 	enc.writeValue(v, func(valenc ValueEncoder, v interface{}) {
 		valenc.Write(enc, v)
 	})
+	enc.autoFlush()
+}
+
+// flush writes buf[off:] to Writer. If Writer is not an io.WriteSeeker,
+// it stops at the first pending list/map head with unknown count, which can
+// not be patched after written. When the flush threshold is set, the written
+// data is dropped from buf, but the data before start, which is passed to
+// ResetBuffer, is kept.
+func (enc *Encoder) flush() (err error) {
+	end := len(enc.buf)
+	if len(enc.heads) > 0 {
+		if _, ok := enc.Writer.(io.WriteSeeker); !ok {
+			end = int(enc.heads[0] - enc.flushed)
+		}
+	}
+	if enc.off < end {
+		_, err = enc.Writer.Write(enc.buf[enc.off:end])
+		enc.off = end
+	}
+	if enc.threshold > 0 && enc.off > enc.start {
+		n := copy(enc.buf[enc.start:], enc.buf[enc.off:])
+		enc.buf = enc.buf[:enc.start+n]
+		enc.flushed += int64(enc.off - enc.start)
+		enc.off = enc.start
+	}
+	return
 }
 
 // Flush writes the encoding data from buf to Writer
@@ -186,13 +280,38 @@ func (enc *Encoder) Flush() (err error) {
 	if enc.Error != nil {
 		return enc.Error
 	}
-	if enc.Writer != nil && enc.off < len(enc.buf) {
-		_, err = enc.Writer.Write(enc.buf[enc.off:])
-		enc.off = len(enc.buf)
+	if enc.Writer != nil {
+		err = enc.flush()
 	}
 	return
 }
 
+// autoFlush writes buf to Writer when the unwritten data reaches the flush
+// threshold. It is called after each value written by encode and write, and
+// after each element or field written by the encoders bypassing them.
+func (enc *Encoder) autoFlush() {
+	if enc.threshold > 0 {
+		enc.flushThreshold()
+	}
+}
+
+func (enc *Encoder) flushThreshold() {
+	if len(enc.buf)-enc.off >= enc.threshold && enc.Writer != nil && enc.Error == nil {
+		enc.Error = enc.flush()
+	}
+}
+
+// AutoFlush sets the flush threshold of encoder. When threshold > 0, the
+// encoding data is written to Writer as soon as the unwritten data reaches
+// threshold bytes while encoding lists, maps and objects, and the written data
+// is dropped from buf, so Bytes & String return the unwritten data only.
+// The lists and maps with unknown count are flushed before they end only if
+// Writer is an io.WriteSeeker, otherwise they are kept in buf until they end.
+func (enc *Encoder) AutoFlush(threshold int) *Encoder {
+	enc.threshold = threshold
+	return enc
+}
+
 // Encode writes the hprose encoding of v to stream
 // if v is already written to stream, it will writes it as reference
 func (enc *Encoder) Encode(v interface{}) (err error) {
@@ -269,29 +388,92 @@ func (enc *Encoder) AddReferenceCount(n int) {
 
 // WriteStructType of t to stream with action
 func (enc *Encoder) WriteStructType(t reflect.Type, action func()) (r int) {
+	return enc.writeStructType(t, action)
+}
+
+// writeStructType of key to stream with action, key is a reflect.Type of
+// struct or a string of class signature.
+func (enc *Encoder) writeStructType(key interface{}, action func()) (r int) {
 	if enc.ref == nil {
-		enc.ref = make(map[reflect.Type]int)
+		enc.ref = make(map[interface{}]int)
 	}
-	if r, ok := enc.ref[t]; ok {
+	if r, ok := enc.ref[key]; ok {
 		return r
 	}
 	action()
 	r = enc.last
 	enc.last++
-	enc.ref[t] = r
+	enc.ref[key] = r
 	return
 }
 
-// Reset the value reference and struct type reference
+// Reset the value reference and struct type reference,
+// the struct type reference is kept in session mode.
 func (enc *Encoder) Reset() *Encoder {
 	if !enc.IsSimple() {
 		enc.refer.Reset()
 	}
-	enc.ref = nil
-	enc.last = 0
+	enc.path.reset()
+	if !enc.session {
+		for key := range enc.ref {
+			delete(enc.ref, key)
+		}
+		enc.last = 0
+	}
+	return enc
+}
+
+func (enc *Encoder) resetBuffer(buf []byte) {
+	enc.buf = buf
+	enc.off = len(buf)
+	enc.start = len(buf)
+	enc.flushed = 0
+	enc.heads = enc.heads[:0]
+	enc.Error = nil
+	enc.Reset()
+}
+
+// ResetBuffer resets the encoder to append the encoding data to dst,
+// the data already in dst is never written to Writer. The references
+// are reset as Reset does.
+func (enc *Encoder) ResetBuffer(dst []byte) *Encoder {
+	enc.resetBuffer(dst)
+	enc.borrowed = true
 	return enc
 }
 
+// ResetWriter resets the encoder to write the encoding data to w, and reuses
+// the buffer of encoder, unless it is passed to ResetBuffer by the caller.
+// The references are reset as Reset does.
+func (enc *Encoder) ResetWriter(w io.Writer) *Encoder {
+	if enc.borrowed {
+		enc.resetBuffer(nil)
+	} else {
+		enc.resetBuffer(enc.buf[:0])
+	}
+	enc.borrowed = false
+	enc.Writer = w
+	return enc
+}
+
+// Session sets the encoder to session mode or not.
+// In session mode, the struct types written to stream are remembered across Reset,
+// so each struct type is written only once per session.
+// The decoder on the other side must be in session mode too.
+func (enc *Encoder) Session(session bool) *Encoder {
+	enc.session = session
+	if !session {
+		enc.ref = nil
+		enc.last = 0
+	}
+	return enc
+}
+
+// IsSession returns the encoder is in session mode or not
+func (enc *Encoder) IsSession() bool {
+	return enc.session
+}
+
 // Simple resets the encoder to simple mode or not
 func (enc *Encoder) Simple(simple bool) *Encoder {
 	if simple {
@@ -314,24 +496,157 @@ func (enc *Encoder) WriteNil() {
 	enc.buf = append(enc.buf, TagNull)
 }
 
+// WriteBytesFrom writes n bytes read from r to encoder as a bytes value.
+// If Writer is set, the bytes are copied to Writer directly without being
+// buffered, so they are not returned by Bytes & String.
+func (enc *Encoder) WriteBytesFrom(r io.Reader, n int64) error {
+	if enc.Error != nil {
+		return enc.Error
+	}
+	enc.AddReferenceCount(1)
+	enc.buf = append(enc.buf, TagBytes)
+	if n > 0 {
+		enc.buf = AppendUint64(enc.buf, uint64(n))
+	}
+	enc.buf = append(enc.buf, TagQuote)
+	var err error
+	if enc.Writer != nil {
+		if err = enc.flush(); err == nil && enc.off == len(enc.buf) {
+			enc.flushed += int64(len(enc.buf)-enc.start) + n
+			enc.buf = enc.buf[:enc.start]
+			enc.off = enc.start
+			_, err = io.CopyN(enc.Writer, r, n)
+		} else if err == nil {
+			err = enc.readBytesFrom(r, n)
+		}
+	} else {
+		err = enc.readBytesFrom(r, n)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	enc.buf = append(enc.buf, TagQuote)
+	enc.Error = err
+	return err
+}
+
+// readBytesFrom reads n bytes from r into buf.
+func (enc *Encoder) readBytesFrom(r io.Reader, n int64) error {
+	l := len(enc.buf)
+	size := l + int(n)
+	if cap(enc.buf) < size {
+		buf := make([]byte, l, size)
+		copy(buf, enc.buf)
+		enc.buf = buf
+	}
+	enc.buf = enc.buf[:size]
+	m, err := io.ReadFull(r, enc.buf[l:])
+	enc.buf = enc.buf[:l+m]
+	return err
+}
+
 func (enc *Encoder) writeHead(n int, tag byte) {
 	enc.buf = append(enc.buf, tag)
-	if n > 0 {
+	if n < 0 {
+		enc.heads = append(enc.heads, enc.flushed+int64(len(enc.buf)))
+		enc.buf = append(enc.buf, unknownCount...)
+	} else if n > 0 {
 		enc.buf = AppendUint64(enc.buf, uint64(n))
 	}
 	enc.buf = append(enc.buf, TagOpenbrace)
 }
 
-// WriteListHead to encoder, n is the count of elements in list
+// unknownCount is the placeholder of list/map count, which is long enough for
+// any count. The count patched after the head is written to an io.WriteSeeker
+// keeps the leading zeros of the placeholder, such as a0000000000000000012{...}.
+// This changes the wire format, the encoders never wrote leading zeros before.
+// The hprose specification defines the count as decimal digits without
+// forbidding the leading zeros, and the decoders read them as a plain decimal,
+// the earlier versions of this package decode the patched count as well.
+var unknownCount = []byte("0000000000000000000")
+
+// writeCountFoot ends the list/map started with unknown count, n is the count
+// of elements written. If the head has been written to Writer, Writer must be
+// an io.WriteSeeker to patch the count.
+func (enc *Encoder) writeCountFoot(n int) {
+	enc.buf = append(enc.buf, TagClosebrace)
+	last := len(enc.heads) - 1
+	if last < 0 {
+		panic("hprose/encoding: foot without head of unknown count")
+	}
+	pos := enc.heads[last]
+	enc.heads = enc.heads[:last]
+	if i := int(pos - enc.flushed); i >= enc.off {
+		var count [20]byte
+		digits := count[:0]
+		if n > 0 {
+			digits = AppendUint64(digits, uint64(n))
+		}
+		j := i + copy(enc.buf[i:], digits)
+		j += copy(enc.buf[j:], enc.buf[i+len(unknownCount):])
+		enc.buf = enc.buf[:j]
+		return
+	}
+	if enc.Error == nil {
+		enc.Error = enc.patchCount(pos, n)
+	}
+}
+
+// WriteListHead to encoder, n is the count of elements in list.
+// If n < 0, the count is unknown, and the list must be ended by WriteListFoot
+// with the count of elements written.
 func (enc *Encoder) WriteListHead(n int) {
 	enc.writeHead(n, TagList)
 }
 
-// WriteMapHead to encoder, n is the count of elements in map
+// WriteListFoot ends the list started by WriteListHead with unknown count,
+// n is the count of elements written. If the list head has been written to
+// Writer, Writer must be an io.WriteSeeker to patch the count.
+func (enc *Encoder) WriteListFoot(n int) {
+	enc.writeCountFoot(n)
+}
+
+// WriteMapHead to encoder, n is the count of elements in map.
+// If n < 0, the count is unknown, and the map must be ended by WriteMapFoot
+// with the count of elements written.
 func (enc *Encoder) WriteMapHead(n int) {
 	enc.writeHead(n, TagMap)
 }
 
+// WriteMapFoot ends the map started by WriteMapHead with unknown count,
+// n is the count of elements written. If the map head has been written to
+// Writer, Writer must be an io.WriteSeeker to patch the count.
+func (enc *Encoder) WriteMapFoot(n int) {
+	enc.writeCountFoot(n)
+}
+
+func (enc *Encoder) patchCount(pos int64, n int) (err error) {
+	ws, ok := enc.Writer.(io.WriteSeeker)
+	if !ok {
+		return ErrUnseekableWriter
+	}
+	if err = enc.flush(); err != nil {
+		return
+	}
+	written := enc.flushed + int64(enc.off)
+	var cur int64
+	if cur, err = ws.Seek(0, io.SeekCurrent); err != nil {
+		return
+	}
+	if _, err = ws.Seek(cur-(written-pos), io.SeekStart); err != nil {
+		return
+	}
+	digits := AppendUint64(nil, uint64(n))
+	count := make([]byte, len(unknownCount)-len(digits), len(unknownCount))
+	copy(count, unknownCount)
+	count = append(count, digits...)
+	if _, err = ws.Write(count); err != nil {
+		return
+	}
+	_, err = ws.Seek(cur, io.SeekStart)
+	return
+}
+
 // WriteObjectHead to encoder, r is the reference number of struct
 func (enc *Encoder) WriteObjectHead(r int) {
 	enc.buf = append(enc.buf, TagObject)
@@ -349,7 +664,14 @@ func (enc *Encoder) EncodeReference(valenc ValueEncoder, v interface{}) {
 	if reflect2.IsNil(v) {
 		enc.WriteNil()
 	} else if ok := enc.WriteReference(v); !ok {
-		valenc.Write(enc, v)
+		if t := reflect.TypeOf(v); t.Kind() == reflect.Ptr {
+			if enc.enter(t, reflect2.PtrOf(v), 0) {
+				valenc.Write(enc, v)
+				enc.leave()
+			}
+		} else {
+			valenc.Write(enc, v)
+		}
 	}
 }
 
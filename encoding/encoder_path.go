@@ -0,0 +1,243 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/encoder_path.go                                 |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// pathEntry is a pointer, map or slice on the encoding path, len is the
+// length of a slice.
+type pathEntry struct {
+	ptr unsafe.Pointer
+	len int
+	t   reflect.Type
+}
+
+// encoderPath holds the pointers, maps and slices being encoded in simple
+// mode, to detect the values which reference themselves. In reference mode,
+// it holds the maps and slices being encoded with their reference numbers in
+// refs. The entries beyond linearPathLength are indexed by visiting instead
+// of being searched.
+type encoderPath struct {
+	entries  []pathEntry
+	refs     []uint64
+	visiting map[pathEntry]int
+}
+
+const linearPathLength = 32
+
+func (p *encoderPath) find(e pathEntry) int {
+	entries := p.entries
+	if len(entries) > linearPathLength {
+		entries = entries[:linearPathLength]
+	}
+	for i := range entries {
+		if entries[i] == e {
+			return i
+		}
+	}
+	if i, ok := p.visiting[e]; ok {
+		return i
+	}
+	return -1
+}
+
+func (p *encoderPath) push(e pathEntry) {
+	if n := len(p.entries); n >= linearPathLength {
+		if p.visiting == nil {
+			p.visiting = make(map[pathEntry]int)
+		}
+		p.visiting[e] = n
+	}
+	p.entries = append(p.entries, e)
+}
+
+func (p *encoderPath) pop() {
+	n := len(p.entries) - 1
+	if n >= linearPathLength {
+		delete(p.visiting, p.entries[n])
+	}
+	p.entries = p.entries[:n]
+}
+
+func (p *encoderPath) reset() {
+	p.entries = p.entries[:0]
+	p.refs = p.refs[:0]
+	p.visiting = nil
+}
+
+// cycleError returns the CycleError of e, which is the same as the i-th
+// entry on the path.
+func (p *encoderPath) cycleError(i int, e pathEntry) CycleError {
+	entries := append(p.entries[i:len(p.entries):len(p.entries)], e)
+	path := make([]reflect.Type, len(entries))
+	steps := make([]string, len(entries)-1)
+	for j := range entries {
+		path[j] = entries[j].t
+		if j > 0 {
+			steps[j-1] = entries[j-1].step(entries[j])
+		}
+	}
+	return CycleError{e.t, path, steps}
+}
+
+// maxStepDepth limits the pointers to non-struct values followed by step.
+const maxStepDepth = 8
+
+// step returns how the value of next is reached from the value of e, such as
+// ".Children", "[2]" or `["key"]`. The steps are not recorded while encoding,
+// they are searched when a cycle is found, so the first one is returned if
+// there are more, or "" if there is none.
+func (e pathEntry) step(next pathEntry) string {
+	switch e.t.Kind() {
+	case reflect.Ptr:
+		return findStep(reflect.NewAt(e.t.Elem(), e.ptr).Elem(), next, "", 0)
+	case reflect.Map:
+		iter := reflect.NewAt(e.t, unsafe.Pointer(&e.ptr)).Elem().MapRange()
+		for iter.Next() {
+			if step := findStep(iter.Value(), next, mapKeyStep(iter.Key()), 0); step != "" {
+				return step
+			}
+		}
+	case reflect.Slice:
+		header := reflect.SliceHeader{Data: uintptr(e.ptr), Len: e.len, Cap: e.len}
+		v := reflect.NewAt(e.t, unsafe.Pointer(&header)).Elem()
+		for i := 0; i < e.len; i++ {
+			if step := findStep(v.Index(i), next, "["+strconv.Itoa(i)+"]", 0); step != "" {
+				return step
+			}
+		}
+	}
+	return ""
+}
+
+// findStep returns step if v is the value of next, or the step to it from v
+// prefixed by step. It does not pass the other pointers to struct, maps and
+// slices, which are on the path themselves if next is reached through them.
+func findStep(v reflect.Value, next pathEntry, step string, depth int) string {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			break
+		}
+		if v.Type() == next.t && v.UnsafePointer() == next.ptr {
+			return step
+		}
+		if v.Type().Elem().Kind() != reflect.Struct && depth < maxStepDepth {
+			return findStep(v.Elem(), next, step, depth+1)
+		}
+	case reflect.Map:
+		if v.Type() == next.t && v.UnsafePointer() == next.ptr {
+			return step
+		}
+	case reflect.Slice:
+		if v.Type() == next.t && v.UnsafePointer() == next.ptr && v.Len() == next.len {
+			return step
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			return findStep(v.Elem(), next, step, depth)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if s := findStep(v.Field(i), next, step+"."+v.Type().Field(i).Name, depth); s != "" {
+				return s
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if s := findStep(v.Index(i), next, step+"["+strconv.Itoa(i)+"]", depth); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// mapKeyStep returns the step to the value of key in a map.
+func mapKeyStep(key reflect.Value) string {
+	if key.Kind() == reflect.Interface {
+		key = key.Elem()
+	}
+	if key.Kind() == reflect.String {
+		return "[" + strconv.Quote(key.String()) + "]"
+	}
+	return fmt.Sprintf("[%v]", key)
+}
+
+// enter pushes the value of type t at ptr to the encoding path in simple
+// mode. If the value is already on the path, it writes nil instead, sets
+// CycleError to enc.Error unless BreakCycles is set, and returns false.
+func (enc *Encoder) enter(t reflect.Type, ptr unsafe.Pointer, n int) bool {
+	if !enc.IsSimple() {
+		return true
+	}
+	e := pathEntry{ptr, n, t}
+	if i := enc.path.find(e); i >= 0 {
+		if !enc.breakCycles && enc.Error == nil {
+			enc.Error = enc.path.cycleError(i, e)
+		}
+		enc.WriteNil()
+		return false
+	}
+	enc.path.push(e)
+	return true
+}
+
+// leave pops the value entered last from the encoding path in simple mode.
+func (enc *Encoder) leave() {
+	if enc.IsSimple() {
+		enc.path.pop()
+	}
+}
+
+// enterValue pushes the map or slice of type t at ptr to the encoding path.
+// The maps and slices are encoded by value, which are not referenced like
+// the pointers, so in reference mode, it writes the reference to the one
+// being encoded if they are the same, and returns false, instead of encoding
+// it endlessly. It works as enter in simple mode.
+func (enc *Encoder) enterValue(t reflect.Type, ptr unsafe.Pointer, n int) bool {
+	if enc.IsSimple() {
+		return enc.enter(t, ptr, n)
+	}
+	e := pathEntry{ptr, n, t}
+	if i := enc.path.find(e); i >= 0 {
+		enc.buf = append(enc.buf, TagRef)
+		enc.buf = AppendUint64(enc.buf, enc.path.refs[i])
+		enc.buf = append(enc.buf, TagSemicolon)
+		return false
+	}
+	enc.path.push(e)
+	enc.path.refs = append(enc.path.refs, enc.refer.last-1)
+	return true
+}
+
+// leaveValue pops the map or slice entered last from the encoding path.
+func (enc *Encoder) leaveValue() {
+	if !enc.IsSimple() {
+		enc.path.refs = enc.path.refs[:len(enc.path.refs)-1]
+	}
+	enc.path.pop()
+}
+
+// BreakCycles sets whether the encoder writes nil for the values which
+// reference themselves in simple mode, instead of failing with CycleError.
+func (enc *Encoder) BreakCycles(enable bool) *Encoder {
+	enc.breakCycles = enable
+	return enc
+}
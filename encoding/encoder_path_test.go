@@ -0,0 +1,131 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/encoder_path_test.go                            |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestNode struct {
+	Name     string
+	Parent   *TestNode
+	Children []*TestNode
+}
+
+func newTestTree() *TestNode {
+	root := &TestNode{Name: "root"}
+	root.Children = []*TestNode{{Name: "a", Parent: root}}
+	return root
+}
+
+func TestEncodeCycleInSimpleMode(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	err := enc.Encode(newTestTree())
+	nodeType := reflect.TypeOf((*TestNode)(nil))
+	assert.Equal(t, CycleError{nodeType, []reflect.Type{
+		nodeType, reflect.TypeOf([]*TestNode(nil)), nodeType, nodeType,
+	}, []string{".Children", "[0]", ".Parent"}}, err)
+	assert.EqualError(t, err, "hprose/encoding: encountered a cycle via *encoding.TestNode at "+
+		"*encoding.TestNode.Children[0].Parent: "+
+		"*encoding.TestNode -> []*encoding.TestNode -> *encoding.TestNode -> *encoding.TestNode")
+
+	sb.Reset()
+	enc = NewEncoder(sb).BreakCycles(true)
+	assert.NoError(t, enc.Encode(newTestTree()))
+	assert.Equal(t, `c8"TestNode"3{s4"name"s6"parent"s8"children"}`+
+		`o0{s4"root"na1{o0{uann}}}`, sb.String())
+
+	sb.Reset()
+	enc = NewEncoder(sb).Simple(false)
+	assert.NoError(t, enc.Encode(newTestTree()))
+	assert.Equal(t, `c8"TestNode"3{s4"name"s6"parent"s8"children"}`+
+		`o0{s4"root"na1{o0{uar3;n}}}`, sb.String())
+}
+
+func TestEncodeSharedValuesInSimpleMode(t *testing.T) {
+	node := &TestNode{Name: "a"}
+	m := map[string]int{"x": 1}
+	s := []int{1, 2}
+	data, err := Append(nil, []interface{}{node, node, m, m, s, s})
+	assert.NoError(t, err)
+	assert.Equal(t, `a6{c8"TestNode"3{s4"name"s6"parent"s8"children"}o0{uann}o0{uann}`+
+		`m1{ux1}m1{ux1}a2{12}a2{12}}`, string(data))
+}
+
+func TestEncodeMapAndSliceCycles(t *testing.T) {
+	m := map[string]interface{}{}
+	m["self"] = m
+	_, err := Append(nil, m)
+	mapType := reflect.TypeOf(m)
+	assert.Equal(t, CycleError{mapType, []reflect.Type{mapType, mapType}, []string{`["self"]`}}, err)
+	assert.EqualError(t, err, `hprose/encoding: encountered a cycle via map[string]interface {} at `+
+		`map[string]interface {}["self"]: map[string]interface {} -> map[string]interface {}`)
+
+	s := []interface{}{1, nil}
+	s[1] = s
+	data, err := Append(nil, s)
+	sliceType := reflect.TypeOf(s)
+	assert.Equal(t, CycleError{sliceType, []reflect.Type{sliceType, sliceType}, []string{"[1]"}}, err)
+	assert.Equal(t, `a2{1n}`, string(data))
+
+	enc := NewEncoder(nil).BreakCycles(true)
+	assert.NoError(t, enc.Encode(m))
+	assert.Equal(t, `m1{s4"self"n}`, enc.String())
+}
+
+func TestEncodeLongCycle(t *testing.T) {
+	head := &TestNode{Name: "0"}
+	node := head
+	for i := 1; i < 100; i++ {
+		node.Children = []*TestNode{{Name: "n"}}
+		node = node.Children[0]
+	}
+	enc := NewEncoder(nil)
+	assert.NoError(t, enc.Encode(head))
+	assert.Empty(t, enc.path.entries)
+
+	node.Parent = head.Children[0].Children[0]
+	enc = NewEncoder(nil)
+	err := enc.Encode(head)
+	assert.IsType(t, CycleError{}, err)
+	assert.Len(t, err.(CycleError).Path, 196)
+	assert.Equal(t, "*encoding.TestNode"+strings.Repeat(".Children[0]", 97)+".Parent",
+		"*encoding.TestNode"+strings.Join(err.(CycleError).Steps, ""))
+	enc.Reset()
+	assert.Empty(t, enc.path.entries)
+	assert.Nil(t, enc.path.visiting)
+}
+
+func TestEncodeCyclePath(t *testing.T) {
+	type Holder struct {
+		Nodes [2]map[int]*TestNode
+	}
+	root := &TestNode{Name: "root"}
+	root.Children = []*TestNode{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	root.Children[2].Parent = root
+	_, err := Append(nil, Holder{[2]map[int]*TestNode{nil, {7: root}}})
+	assert.EqualError(t, err, "hprose/encoding: encountered a cycle via *encoding.TestNode at "+
+		"*encoding.TestNode.Children[2].Parent: "+
+		"*encoding.TestNode -> []*encoding.TestNode -> *encoding.TestNode -> *encoding.TestNode")
+
+	m := map[interface{}]interface{}{}
+	m[1] = []interface{}{struct{ M interface{} }{m}}
+	_, err = Append(nil, m)
+	assert.Equal(t, []string{"[1]", "[0].M"}, err.(CycleError).Steps)
+}
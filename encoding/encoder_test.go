@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/encoder_test.go                                 |
 |                                                          |
-| LastModified: Apr 12, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,8 +15,10 @@ package encoding
 
 import (
 	"errors"
+	"io/ioutil"
 	"math"
 	"math/big"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
@@ -359,6 +361,24 @@ func TestReset(t *testing.T) {
 	assert.Equal(t, `na{}a3{1s5"hello"t}a3{1r2;t}a3{1s5"hello"t}r0;`, sb.String())
 }
 
+func TestSession(t *testing.T) {
+	type TestStruct struct {
+		A int
+		B string
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false).Session(true)
+	assert.True(t, enc.IsSession())
+	assert.NoError(t, enc.Encode(TestStruct{1, "hello"}))
+	enc.Reset()
+	assert.NoError(t, enc.Encode(TestStruct{2, "hello"}))
+	enc.Session(false).Reset()
+	assert.NoError(t, enc.Encode(TestStruct{3, "hello"}))
+	assert.Equal(t, `c10"TestStruct"2{s1"a"s1"b"}o0{1s5"hello"}`+
+		`o0{2s5"hello"}`+
+		`c10"TestStruct"2{s1"a"s1"b"}o0{3s5"hello"}`, sb.String())
+}
+
 func TestEncodeByteArray(t *testing.T) {
 	sb := &strings.Builder{}
 	enc := NewEncoder(sb).Simple(false)
@@ -591,7 +611,7 @@ func TestUnsupportedTypeError(t *testing.T) {
 	sb := &strings.Builder{}
 	enc := NewEncoder(sb).Simple(false)
 	f := func() {}
-	var ch chan int
+	ch := make(chan<- int)
 	assert.EqualError(t, enc.Encode(f), (UnsupportedTypeError{reflect.TypeOf(f)}).Error())
 	assert.EqualError(t, enc.Encode(ch), (UnsupportedTypeError{reflect.TypeOf(ch)}).Error())
 	assert.EqualError(t, enc.Encode(&f), (UnsupportedTypeError{reflect.TypeOf(&f)}).Error())
@@ -634,6 +654,207 @@ func TestWriteTag(t *testing.T) {
 	assert.Equal(t, `Hm1{s2"id"s5"12345"}`, enc.String())
 }
 
+type testFlushWriter struct {
+	writes []string
+}
+
+func (w *testFlushWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestAutoFlush(t *testing.T) {
+	w := &testFlushWriter{}
+	enc := NewEncoder(w).Simple(true).AutoFlush(4)
+	assert.NoError(t, enc.Encode([]int{1, 2, 3, 4, 5, 6}))
+	assert.Equal(t, []string{"a6{1", "2345", "6}"}, w.writes)
+	assert.Equal(t, "", enc.String())
+	w.writes = nil
+	assert.NoError(t, enc.Encode(map[string]string{"a": "b"}))
+	assert.Equal(t, []string{`m1{uaub`, "}"}, w.writes)
+}
+
+func TestWriteListHeadUnknownCount(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(true)
+	enc.WriteListHead(-1)
+	enc.WriteListHead(-1)
+	enc.WriteListFoot(0)
+	for i := 0; i < 3; i++ {
+		enc.Encode(i)
+	}
+	enc.WriteListFoot(4)
+	assert.NoError(t, enc.Flush())
+	assert.Equal(t, `a4{a{}012}`, sb.String())
+
+	w := &testFlushWriter{}
+	enc = NewEncoder(w).Simple(true).AutoFlush(1)
+	enc.Encode(1)
+	enc.WriteListHead(-1)
+	enc.Encode([]int{2, 3})
+	assert.Equal(t, []string{`1`, `a`}, w.writes)
+	enc.WriteListFoot(1)
+	assert.NoError(t, enc.Flush())
+	assert.Equal(t, []string{`1`, `a`, `1{a2{23}}`}, w.writes)
+
+	sb.Reset()
+	ch := make(chan int, 1)
+	ch <- 1
+	close(ch)
+	enc = NewEncoder(sb).Simple(true).AutoFlush(1)
+	assert.NoError(t, enc.Encode(ch))
+	assert.Equal(t, `a1{1}`, sb.String())
+}
+
+func TestWriteListFootUnseekableWriter(t *testing.T) {
+	f, err := ioutil.TempFile("", "hprose")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	enc := NewEncoder(f).Simple(true).AutoFlush(1)
+	enc.WriteListHead(-1)
+	enc.Encode(1)
+	enc.Writer = &strings.Builder{}
+	enc.WriteListFoot(1)
+	assert.Equal(t, ErrUnseekableWriter, enc.Error)
+}
+
+func TestWriteListHeadUnknownCountSeek(t *testing.T) {
+	f, err := ioutil.TempFile("", "hprose")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	enc := NewEncoder(f).Simple(true)
+	enc.WriteListHead(-1)
+	for i := 0; i < 12; i++ {
+		enc.Encode(i)
+	}
+	enc.WriteListFoot(12)
+	assert.NoError(t, enc.Flush())
+	data, err := ioutil.ReadFile(f.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, `a0000000000000000012{0123456789i10;i11;}`, string(data))
+	var v []int
+	NewDecoder(data).Decode(&v)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, v)
+}
+
+func TestDecodeZeroPaddedCount(t *testing.T) {
+	var ints []int
+	NewDecoder([]byte(`a0000000000000000002{12}`)).Decode(&ints)
+	assert.Equal(t, []int{1, 2}, ints)
+	var m map[string]int
+	NewDecoder([]byte(`m0000000000000000001{ua1}`)).Decode(&m)
+	assert.Equal(t, map[string]int{"a": 1}, m)
+	var i interface{}
+	dec := NewDecoder([]byte(`a0000000000000000002{a0000000000000000000{}m0000000000000000001{1u2}}`))
+	dec.Decode(&i)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []interface{}{[]interface{}(nil), map[interface{}]interface{}{1: "2"}}, i)
+	v := new(Value)
+	NewDecoder([]byte(`a0000000000000000001{1}`)).Decode(v)
+	assert.Equal(t, NewList(NewInt(1)), v)
+}
+
+func TestAutoFlushUnknownCountSeek(t *testing.T) {
+	f, err := ioutil.TempFile("", "hprose")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	enc := NewEncoder(f).Simple(true).AutoFlush(4)
+	enc.WriteListHead(-1)
+	for i := 0; i < 12; i++ {
+		enc.Encode(i)
+	}
+	enc.WriteListFoot(12)
+	assert.NoError(t, enc.Flush())
+	data, err := ioutil.ReadFile(f.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, `a0000000000000000012{0123456789i10;i11;}`, string(data))
+}
+
+func TestAutoFlushResetBuffer(t *testing.T) {
+	w := &testFlushWriter{}
+	enc := NewEncoder(w).AutoFlush(4)
+	enc.ResetBuffer([]byte("HEADER:"))
+	assert.NoError(t, enc.Encode([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}))
+	assert.Equal(t, "HEADER:", enc.String())
+	assert.Equal(t, `a12{0123456789i10;i11;}`, strings.Join(w.writes, ""))
+}
+
+func TestAppend(t *testing.T) {
+	type TestStruct struct {
+		A int
+		B string
+	}
+	buf, err := Append([]byte("x"), []int{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, `xa3{123}`, string(buf))
+	buf, err = Append(buf[:0], &TestStruct{1, "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, `c10"TestStruct"2{s1"a"s1"b"}o0{1s5"hello"}`, string(buf))
+	_, err = Append(nil, func() {})
+	assert.Error(t, err)
+}
+
+func TestAppendAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("skipping allocation test in race mode")
+	}
+	type TestStruct struct {
+		A int
+		B string
+	}
+	buf := make([]byte, 0, 1024)
+	s := &TestStruct{1, "hello"}
+	var list interface{} = []int{1, 2, 3}
+	var v interface{} = "hello"
+	Append(buf, s)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = Append(buf[:0], s)
+		buf, _ = Append(buf, list)
+		buf, _ = Append(buf, v)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestResetBuffer(t *testing.T) {
+	enc := NewEncoder(nil)
+	buf := make([]byte, 0, 1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		enc.ResetBuffer(buf[:0])
+		enc.Encode(123)
+		enc.Encode(3.14)
+		buf = enc.Bytes()
+	})
+	assert.Equal(t, float64(0), allocs)
+	assert.Equal(t, `i123;d3.14;`, string(buf))
+	sb := &strings.Builder{}
+	enc.ResetBuffer([]byte("prefix")).Writer = sb
+	enc.Encode(1)
+	assert.Equal(t, `prefix1`, enc.String())
+	assert.Equal(t, `1`, sb.String())
+}
+
+func TestResetWriter(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	enc.Encode("hello")
+	enc.Encode(func() {})
+	sb2 := &strings.Builder{}
+	enc.ResetWriter(sb2)
+	assert.NoError(t, enc.Encode("hello"))
+	assert.Equal(t, `s5"hello"`, sb2.String())
+	assert.Equal(t, `s5"hello"`, enc.String())
+
+	dst := make([]byte, 0, 64)
+	enc.ResetBuffer(dst)
+	assert.NoError(t, enc.Encode(1))
+	enc.ResetWriter(&strings.Builder{})
+	assert.NoError(t, enc.Encode(2))
+	assert.Equal(t, `1`, string(dst[:1]))
+}
+
 func BenchmarkHproseEncodeSlice(b *testing.B) {
 	sb := &strings.Builder{}
 	enc := NewEncoder(sb).Simple(false)
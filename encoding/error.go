@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/error.go                                        |
 |                                                          |
-| LastModified: Apr 25, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -16,6 +16,7 @@ package encoding
 import (
 	"errors"
 	"reflect"
+	"strings"
 )
 
 // An UnsupportedTypeError is returned by Encoder when attempting
@@ -28,9 +29,64 @@ func (e UnsupportedTypeError) Error() string {
 	return "hprose/encoding: unsupported type: " + e.Type.String()
 }
 
+// A ClassNameError is returned by TypeRegistry.GetName and set to
+// Encoder.Error when the class name of Type is registered to another type.
+type ClassNameError struct {
+	Type reflect.Type
+	Name string
+}
+
+func (e ClassNameError) Error() string {
+	return "hprose/encoding: class name " + e.Name + " of " + e.Type.String() + " is registered to another type"
+}
+
+// A FieldCodecError is returned by Register and set to Encoder.Error and
+// Decoder.Error when the codec named Name of Field is not registered, or
+// Unsupported is true if it does not support the field Type.
+type FieldCodecError struct {
+	Field       string
+	Type        reflect.Type
+	Name        string
+	Unsupported bool
+}
+
+func (e FieldCodecError) Error() string {
+	if e.Unsupported {
+		return "hprose/encoding: field codec " + e.Name + " does not support field " + e.Field + " of type " + e.Type.String()
+	}
+	return "hprose/encoding: unknown field codec " + e.Name + " of field " + e.Field
+}
+
+// A CycleError is returned by Encoder in simple mode when a value references
+// itself. Path is the types of the values on the cycle, it starts and ends
+// with Type. Steps are how each value on Path is reached from the previous
+// one, such as ".Children", "[2]" or `["key"]`.
+type CycleError struct {
+	Type  reflect.Type
+	Path  []reflect.Type
+	Steps []string
+}
+
+func (e CycleError) Error() string {
+	path := make([]string, len(e.Path))
+	for i, t := range e.Path {
+		path[i] = t.String()
+	}
+	return "hprose/encoding: encountered a cycle via " + e.Type.String() + " at " +
+		e.Type.String() + strings.Join(e.Steps, "") + ": " + strings.Join(path, " -> ")
+}
+
 // ErrInvalidUTF8 means that a decoder encountered invalid UTF-8.
 var ErrInvalidUTF8 = errors.New("hprose/encoding: invalid UTF-8")
 
+// ErrUnseekableWriter is set to Encoder.Error when the count of a list or map
+// has to be patched after its head is flushed to a Writer which is not an
+// io.WriteSeeker.
+var ErrUnseekableWriter = errors.New("hprose/encoding: unknown count needs an io.WriteSeeker to flush")
+
+// ErrInvalidPath is returned by Value.Set when the path does not fit the value.
+var ErrInvalidPath = errors.New("hprose/encoding: invalid value path")
+
 // A CastError is returned by Decoder when can not cast source type to destination type.
 type CastError struct {
 	Source      reflect.Type
@@ -0,0 +1,165 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/field_codec.go                                  |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// fieldCodec is a named codec of struct fields, accept reports whether it
+// supports the field type, nil accepts any type.
+type fieldCodec struct {
+	encode EncodeHandler
+	decode DecodeHandler
+	accept func(t reflect.Type) bool
+}
+
+// RegisterFieldCodec registers the named codec of struct fields to the
+// codec, which is selected by the field tag such as `hprose:"ts,codec=unixms"`.
+// encode is called with the field value, and decode is called with the field
+// type and the pointer to the field, so decode must only be used with the
+// field types it supports. If encode or decode is nil, the field is encoded or
+// decoded as usual.
+func (c *Codec) RegisterFieldCodec(name string, encode EncodeHandler, decode DecodeHandler) {
+	c.registerFieldCodec(name, encode, decode, nil)
+}
+
+// RegisterFieldCodec registers the named codec of struct fields to
+// DefaultCodec.
+func RegisterFieldCodec(name string, encode EncodeHandler, decode DecodeHandler) {
+	DefaultCodec.RegisterFieldCodec(name, encode, decode)
+}
+
+func (c *Codec) registerFieldCodec(name string, encode EncodeHandler, decode DecodeHandler, accept func(t reflect.Type) bool) {
+	c.fieldCodecs.Store(name, fieldCodec{encode, decode, accept})
+}
+
+// getFieldCodec returns the codec named name for the field f, which is
+// registered to the codec, or to DefaultCodec if there is none.
+func (c *Codec) getFieldCodec(name string, f reflect2.StructField) (fieldCodec, error) {
+	codec, ok := c.fieldCodecs.Load(name)
+	if !ok && c != DefaultCodec {
+		codec, ok = DefaultCodec.fieldCodecs.Load(name)
+	}
+	if !ok {
+		return fieldCodec{}, FieldCodecError{f.Name(), f.Type().Type1(), name, false}
+	}
+	fc := codec.(fieldCodec)
+	if fc.accept != nil && !fc.accept(f.Type().Type1()) {
+		return fieldCodec{}, FieldCodecError{f.Name(), f.Type().Type1(), name, true}
+	}
+	return fc, nil
+}
+
+// invalidFieldCodec returns the codec which sets err to the Encoder or
+// Decoder, the field is encoded as nil, and its value is skipped on decoding.
+func invalidFieldCodec(err error) fieldCodec {
+	return fieldCodec{
+		encode: func(enc *Encoder, v interface{}) {
+			if enc.Error == nil {
+				enc.Error = err
+			}
+			enc.WriteNil()
+		},
+		decode: func(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+			if dec.Error == nil {
+				dec.Error = err
+			}
+			var v interface{}
+			dec.Decode(&v)
+		},
+	}
+}
+
+// unixMilliEncode encodes time.Time as Unix milliseconds.
+func unixMilliEncode(enc *Encoder, v interface{}) {
+	enc.WriteInt64(v.(time.Time).UnixMilli())
+}
+
+// unixMilliDecode decodes time.Time from Unix milliseconds, the other forms
+// of time are decoded as usual.
+func unixMilliDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+	tag := dec.NextByte()
+	if intDigits[tag] != invalidDigit || tag == TagInteger || tag == TagLong || tag == TagDouble {
+		*(*time.Time)(p) = time.UnixMilli(dec.decodeInt64(int64Type, tag))
+		return
+	}
+	*(*time.Time)(p) = dec.decodeTime(t, tag)
+}
+
+// integerStringEncode encodes integers as strings.
+func integerStringEncode(enc *Encoder, v interface{}) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		enc.EncodeString(strconv.FormatInt(rv.Int(), 10))
+	default:
+		enc.EncodeString(strconv.FormatUint(rv.Uint(), 10))
+	}
+}
+
+// integerStringDecode decodes integers from strings or numbers.
+func integerStringDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+	dec.getCodec().GetDecodeHandler(t)(dec, t, p)
+}
+
+func isInteger(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// hexEncode encodes []byte as a hex string.
+func hexEncode(enc *Encoder, v interface{}) {
+	if data := v.([]byte); data != nil {
+		enc.EncodeString(hex.EncodeToString(data))
+	} else {
+		enc.WriteNil()
+	}
+}
+
+// hexDecode decodes []byte from a hex string.
+func hexDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+	tag := dec.NextByte()
+	if tag == TagNull {
+		*(*[]byte)(p) = nil
+		return
+	}
+	s := dec.decodeString(stringType, tag)
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		dec.decodeStringError(s, t.String())
+		return
+	}
+	*(*[]byte)(p) = data
+}
+
+func init() {
+	DefaultCodec.registerFieldCodec("unixms", unixMilliEncode, unixMilliDecode, func(t reflect.Type) bool {
+		return t == timeType
+	})
+	DefaultCodec.registerFieldCodec("string", integerStringEncode, integerStringDecode, isInteger)
+	DefaultCodec.registerFieldCodec("hex", hexEncode, hexDecode, func(t reflect.Type) bool {
+		return t == bytesType
+	})
+}
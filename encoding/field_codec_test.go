@@ -0,0 +1,107 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/field_codec_test.go                             |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestFieldCodecStruct struct {
+	TS   time.Time `hprose:"ts,codec=unixms"`
+	ID   int64     `hprose:"id,codec=string"`
+	Hash []byte    `hprose:"hash,codec=hex"`
+	Name string    `hprose:"name,codec=upper"`
+}
+
+func TestFieldCodec(t *testing.T) {
+	RegisterFieldCodec("upper", func(enc *Encoder, v interface{}) {
+		enc.EncodeString(strings.ToUpper(v.(string)))
+	}, nil)
+	ts := time.UnixMilli(1600000000123)
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	enc.Encode(TestFieldCodecStruct{ts, 9007199254740993, []byte{0xca, 0xfe}, "tom"})
+	assert.Equal(t, `c20"TestFieldCodecStruct"4{s2"ts"s2"id"s4"hash"s4"name"}`+
+		`o0{l1600000000123;s16"9007199254740993"s4"cafe"s3"TOM"}`, sb.String())
+	dec := NewDecoder(([]byte)(sb.String()))
+	var v TestFieldCodecStruct
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.True(t, ts.Equal(v.TS))
+	assert.Equal(t, int64(9007199254740993), v.ID)
+	assert.Equal(t, []byte{0xca, 0xfe}, v.Hash)
+	assert.Equal(t, "TOM", v.Name)
+
+	dec = NewDecoder([]byte(`m2{s2"id"5s4"hash"s2"zz"}`))
+	dec.Decode(&v)
+	assert.Equal(t, int64(5), v.ID)
+	assert.EqualError(t, dec.Error, `hprose/encoding: can not parse "zz" to []uint8`)
+}
+
+func TestInvalidFieldCodec(t *testing.T) {
+	type TestStruct1 struct {
+		A int `hprose:"a,codec=unknown"`
+	}
+	type TestStruct2 struct {
+		A int `hprose:"a,codec=unixms"`
+	}
+	enc := NewEncoder(nil)
+	enc.Encode(TestStruct1{})
+	assert.EqualError(t, enc.Error, "hprose/encoding: unknown field codec unknown of field A")
+	enc = NewEncoder(nil)
+	enc.Encode(TestStruct2{})
+	assert.EqualError(t, enc.Error, "hprose/encoding: field codec unixms does not support field A of type int")
+	dec := NewDecoder([]byte(`m1{ua3}`))
+	var v1 TestStruct1
+	dec.Decode(&v1)
+	assert.EqualError(t, dec.Error, "hprose/encoding: unknown field codec unknown of field A")
+	codec := Config{}.Froze()
+	assert.Equal(t, FieldCodecError{"A", reflect.TypeOf(0), "unixms", true}, codec.Register((*TestStruct2)(nil), "TestStruct2"))
+	RegisterFieldCodec("any", nil, func(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+		dec.Decode(reflect.NewAt(t, p).Interface())
+	})
+	type TestStruct3 struct {
+		A int `hprose:"a,codec=any"`
+	}
+	dec = NewDecoder([]byte(`m1{ua3}`))
+	var v TestStruct3
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestStruct3{3}, v)
+}
+
+func TestCodecFieldCodec(t *testing.T) {
+	type TestStruct struct {
+		Name string `hprose:"name,codec=lower"`
+		Hash []byte `hprose:"hash,codec=hex"`
+	}
+	codec := Config{}.Froze()
+	codec.RegisterFieldCodec("lower", func(enc *Encoder, v interface{}) {
+		enc.EncodeString(strings.ToLower(v.(string)))
+	}, nil)
+	sb := &strings.Builder{}
+	enc := codec.NewEncoder(sb)
+	enc.Encode(TestStruct{"TOM", []byte{0xca, 0xfe}})
+	assert.NoError(t, enc.Error)
+	assert.Equal(t, `c10"TestStruct"2{s4"name"s4"hash"}o0{s3"tom"s4"cafe"}`, sb.String())
+	enc = NewEncoder(nil)
+	enc.Encode(TestStruct{})
+	assert.EqualError(t, enc.Error, "hprose/encoding: unknown field codec lower of field Name")
+}
@@ -0,0 +1,94 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/field_naming.go                                 |
+|                                                          |
+| LastModified: Oct 18, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FieldNaming returns the serialized name of a struct field without alias
+// tag from its Go name.
+type FieldNaming func(name string) string
+
+// KeepCase returns the field name unchanged, such as "UserID".
+func KeepCase(name string) string {
+	return name
+}
+
+// LowerFirst lowercases the first ASCII letter of the field name, such as
+// "userID". It is the default field naming.
+func LowerFirst(name string) string {
+	if name[0] >= 'A' && name[0] <= 'Z' {
+		name = string(name[0]-'A'+'a') + name[1:]
+	}
+	return name
+}
+
+// LowerCamelCase returns the field name in lower camel case, with its
+// initialisms capitalized as words, such as "userId".
+func LowerCamelCase(name string) string {
+	words := splitWords(name)
+	var sb strings.Builder
+	for i, word := range words {
+		if i == 0 {
+			sb.WriteString(strings.ToLower(word))
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	return sb.String()
+}
+
+// SnakeCase returns the field name in snake case, such as "user_id".
+func SnakeCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "_"))
+}
+
+// KebabCase returns the field name in kebab case, such as "user-id".
+func KebabCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// splitWords splits a Go identifier into words at the case boundaries and
+// underscores, the initialisms are kept together, so "HTTPServerID" is split
+// into "HTTP", "Server" and "ID".
+func splitWords(name string) (words []string) {
+	runes := []rune(name)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prev := runes[i-1]
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return
+}
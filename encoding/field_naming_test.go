@@ -0,0 +1,83 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/field_naming_test.go                            |
+|                                                          |
+| LastModified: Oct 18, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldNaming(t *testing.T) {
+	assert.Equal(t, "UserID", KeepCase("UserID"))
+	assert.Equal(t, "userID", LowerFirst("UserID"))
+	assert.Equal(t, "userId", LowerCamelCase("UserID"))
+	assert.Equal(t, "user_id", SnakeCase("UserID"))
+	assert.Equal(t, "user-id", KebabCase("UserID"))
+	assert.Equal(t, "httpServerId", LowerCamelCase("HTTPServerID"))
+	assert.Equal(t, "http_server_id", SnakeCase("HTTPServerID"))
+	assert.Equal(t, "address2_line", SnakeCase("Address2Line"))
+	assert.Equal(t, "first_name", SnakeCase("First_Name"))
+	assert.Equal(t, "a", SnakeCase("A"))
+}
+
+type TestFieldNamingStruct struct {
+	UserID   int
+	FullName string `hprose:"name"`
+}
+
+func TestRegisterWithNaming(t *testing.T) {
+	registry := NewTypeRegistry(ShortName, false)
+	registry.RegisterWithNaming((*TestFieldNamingStruct)(nil), "User", SnakeCase)
+	enc := NewEncoder(nil)
+	enc.Registry = registry
+	enc.Encode(TestFieldNamingStruct{1, "Tom"})
+	assert.Equal(t, `c4"User"2{s7"user_id"s4"name"}o0{1s3"Tom"}`, enc.String())
+	dec := NewDecoder(enc.Bytes())
+	dec.Registry = registry
+	var v TestFieldNamingStruct
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestFieldNamingStruct{1, "Tom"}, v)
+	Register((*TestFieldNamingStruct)(nil), "TestFieldNamingStruct")
+}
+
+type TestCaseInsensitiveStruct struct {
+	UserID int
+	Name   string
+}
+
+func TestTypeRegistryFieldNaming(t *testing.T) {
+	registry := NewTypeRegistry(ShortName, false).FieldNaming(LowerCamelCase).CaseInsensitive(true)
+	registry.Register((*TestCaseInsensitiveStruct)(nil), "User")
+	enc := NewEncoder(nil)
+	enc.Registry = registry
+	enc.Encode(TestCaseInsensitiveStruct{1, "Tom"})
+	assert.Equal(t, `c4"User"2{s6"userId"s4"name"}o0{1s3"Tom"}`, enc.String())
+	dec := NewDecoder([]byte(`c4"User"2{s7"USER_ID"s4"NAME"}o0{1s3"Tom"}m2{s6"USERID"2s4"Name"s5"Jerry"}`))
+	dec.Registry = registry
+	var v TestCaseInsensitiveStruct
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestCaseInsensitiveStruct{0, "Tom"}, v)
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestCaseInsensitiveStruct{2, "Jerry"}, v)
+	Register((*TestCaseInsensitiveStruct)(nil), "TestCaseInsensitiveStruct")
+	dec = NewDecoder([]byte(`m2{s6"USERID"3s4"name"s5"Spike"}`))
+	v = TestCaseInsensitiveStruct{}
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestCaseInsensitiveStruct{0, "Spike"}, v)
+}
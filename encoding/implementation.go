@@ -0,0 +1,297 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/implementation.go                               |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/modern-go/reflect2"
+)
+
+type implementations struct {
+	discriminator string
+	types         map[string]reflect.Type
+	lock          sync.RWMutex
+}
+
+func interfaceTypeOf(iface interface{}) reflect.Type {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("hprose/encoding: invalid interface: %v", t))
+	}
+	return t.Elem()
+}
+
+func (c *Codec) loadImplementations(t reflect.Type) *implementations {
+	impls, _ := c.implementations.LoadOrStore(t, &implementations{
+		types: make(map[string]reflect.Type),
+	})
+	return impls.(*implementations)
+}
+
+// RegisterImplementation registers the type of impl as an implementation of
+// the interface type pointed by iface, such as (*Shape)(nil), to the codec.
+// It is chosen when decoding an object of class name, or an object/map whose
+// discriminator field equals name, into this interface type.
+func (c *Codec) RegisterImplementation(iface interface{}, name string, impl interface{}) {
+	t := interfaceTypeOf(iface)
+	it := reflect.TypeOf(impl)
+	if it == nil || !it.Implements(t) {
+		panic(fmt.Sprintf("hprose/encoding: %v does not implement %s", it, t.String()))
+	}
+	impls := c.loadImplementations(t)
+	impls.lock.Lock()
+	defer impls.lock.Unlock()
+	impls.types[name] = it
+}
+
+// RegisterDiscriminator sets the discriminator field of the interface type
+// pointed by iface, such as (*Shape)(nil), to the codec.
+func (c *Codec) RegisterDiscriminator(iface interface{}, field string) {
+	t := interfaceTypeOf(iface)
+	impls := c.loadImplementations(t)
+	impls.lock.Lock()
+	defer impls.lock.Unlock()
+	impls.discriminator = field
+}
+
+// RegisterImplementation registers the type of impl as an implementation of
+// the interface type pointed by iface to DefaultCodec.
+func RegisterImplementation(iface interface{}, name string, impl interface{}) {
+	DefaultCodec.RegisterImplementation(iface, name, impl)
+}
+
+// RegisterDiscriminator sets the discriminator field of the interface type
+// pointed by iface to DefaultCodec.
+func RegisterDiscriminator(iface interface{}, field string) {
+	DefaultCodec.RegisterDiscriminator(iface, field)
+}
+
+// getImplementations returns the implementations of interface type t
+// registered to the codec, or to DefaultCodec if there are none.
+func (c *Codec) getImplementations(t reflect.Type) *implementations {
+	if impls, ok := c.implementations.Load(t); ok {
+		return impls.(*implementations)
+	}
+	if c != DefaultCodec {
+		return DefaultCodec.getImplementations(t)
+	}
+	return nil
+}
+
+func (impls *implementations) get(name interface{}) reflect.Type {
+	if impls == nil {
+		return nil
+	}
+	if name, ok := name.(string); ok {
+		impls.lock.RLock()
+		defer impls.lock.RUnlock()
+		return impls.types[name]
+	}
+	return nil
+}
+
+func (impls *implementations) getDiscriminator() string {
+	if impls == nil {
+		return ""
+	}
+	impls.lock.RLock()
+	defer impls.lock.RUnlock()
+	return impls.discriminator
+}
+
+func (dec *Decoder) readObjectAs(structInfo structInfo, t reflect.Type) interface{} {
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	structInfo.t = reflect2.Type2(st).(*reflect2.UnsafeStructType)
+	structInfo.fields = dec.getCodec().getFieldMap(st)
+	structInfo.slots = makeFieldSlots(structInfo.names, structInfo.fields)
+	obj := dec.readObject(structInfo)
+	if t.Kind() != reflect.Ptr {
+		return reflect.ValueOf(obj).Elem().Interface()
+	}
+	return obj
+}
+
+// decoderRecord records the bytes read by a Decoder, so that they can be
+// decoded again into another type.
+type decoderRecord struct {
+	prev    *decoderRecord
+	data    []byte
+	head    int
+	copied  bool
+	refs    int
+	classes int
+}
+
+// startRecording starts recording the bytes read by dec.
+func (dec *Decoder) startRecording() *decoderRecord {
+	r := &decoderRecord{prev: dec.record, head: dec.head, classes: len(dec.ref)}
+	if !dec.IsSimple() {
+		r.refs = len(dec.refer.ref)
+	}
+	dec.record = r
+	return r
+}
+
+// stopRecording stops recording the bytes by r. The bytes still in the
+// buffer are not copied, since they are replayed before reading more.
+func (dec *Decoder) stopRecording(r *decoderRecord) {
+	if r.copied {
+		r.data = append(r.data, dec.buf[r.head:dec.head]...)
+	} else {
+		r.data = dec.buf[r.head:dec.head]
+	}
+	dec.record = r.prev
+}
+
+// replayAs decodes the bytes recorded by r after tag again into a value of
+// type t, which replaces the values decoded from them before, and returns it.
+// The references to a value of non-pointer type t are decoded as its copies.
+func (dec *Decoder) replayAs(r *decoderRecord, t reflect.Type, tag byte) interface{} {
+	if !dec.IsSimple() {
+		dec.refer.ref = dec.refer.ref[:r.refs]
+	}
+	dec.ref = dec.ref[:r.classes]
+	reader, buf, head, tail := dec.reader, dec.buf, dec.head, dec.tail
+	dec.reader, dec.buf, dec.head, dec.tail = nil, r.data, 0, len(r.data)
+	p := reflect.New(t)
+	dec.decode(p.Interface(), tag)
+	dec.reader, dec.buf, dec.head, dec.tail = reader, buf, head, tail
+	v := p.Elem().Interface()
+	if t.Kind() != reflect.Ptr && !dec.IsSimple() && len(dec.refer.ref) > r.refs {
+		dec.SetReference(r.refs, v)
+	}
+	return v
+}
+
+// isStructType reports whether t is a struct type or a pointer to it.
+func isStructType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// implementationDecoder is the implementation of ValueDecoder for non-empty interface.
+type implementationDecoder struct {
+	t reflect.Type
+}
+
+// discriminate returns m, or the value of the implementation its
+// discriminator field chooses, which is decoded again from the bytes of m
+// recorded by r.
+func (valdec implementationDecoder) discriminate(dec *Decoder, r *decoderRecord, tag byte, m map[string]interface{}, impls *implementations) interface{} {
+	if field := impls.getDiscriminator(); field != "" {
+		if t := impls.get(m[field]); t != nil {
+			return dec.replayAs(r, t, tag)
+		}
+	}
+	return m
+}
+
+func (valdec implementationDecoder) decodeObject(dec *Decoder, impls *implementations) interface{} {
+	r := dec.startRecording()
+	class, ok := dec.readClassIndex()
+	if !ok {
+		dec.stopRecording(r)
+		return nil
+	}
+	structInfo := dec.getStructInfo(class)
+	t := impls.get(structInfo.name)
+	switch {
+	case t != nil && isStructType(t):
+		dec.stopRecording(r)
+		return dec.readObjectAs(structInfo, t)
+	case t == nil && structInfo.fields != nil:
+		dec.stopRecording(r)
+		return dec.readObject(structInfo)
+	}
+	m := dec.readObjectAsMap(structInfo)
+	dec.stopRecording(r)
+	if t != nil {
+		return dec.replayAs(r, t, TagObject)
+	}
+	return valdec.discriminate(dec, r, TagObject, m, impls)
+}
+
+func (valdec implementationDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	impls := dec.getCodec().getImplementations(valdec.t)
+	var v interface{}
+	switch tag {
+	case TagClass:
+		dec.ReadStruct()
+		valdec.Decode(dec, p, dec.NextByte())
+		return
+	case TagObject:
+		v = valdec.decodeObject(dec, impls)
+	case TagMap:
+		var m map[string]interface{}
+		r := dec.startRecording()
+		sifmdec.Decode(dec, &m, tag)
+		dec.stopRecording(r)
+		v = valdec.discriminate(dec, r, tag, m, impls)
+	default:
+		v = dec.decodeInterface(interfaceType, tag)
+	}
+	if dec.Error != nil {
+		return
+	}
+	rv := reflect.ValueOf(p).Elem()
+	if v == nil {
+		rv.Set(reflect.Zero(valdec.t))
+		return
+	}
+	vv := reflect.ValueOf(v)
+	switch {
+	case vv.Type().Implements(valdec.t):
+		rv.Set(vv)
+	case vv.Kind() == reflect.Ptr && vv.Type().Elem().Implements(valdec.t):
+		rv.Set(vv.Elem())
+	default:
+		dec.Error = CastError{
+			Source:      vv.Type(),
+			Destination: valdec.t,
+		}
+	}
+}
+
+func (valdec implementationDecoder) Type() reflect.Type {
+	return valdec.t
+}
+
+func (c *Codec) getInterfaceDecoder(t reflect.Type) ValueDecoder {
+	if t.NumMethod() == 0 {
+		return interfaceDecoder{t}
+	}
+	return implementationDecoder{t}
+}
+
+func (c *Codec) getInterfacePtrDecoder(t reflect.Type) ValueDecoder {
+	et := t.Elem()
+	if et.NumMethod() == 0 {
+		return interfacePtrDecoder{t}
+	}
+	elemDecoder := c.getInterfaceDecoder(et)
+	c.storeValueDecoder(elemDecoder)
+	return ptrDecoder{
+		reflect2.Type2(t).(*reflect2.UnsafePtrType),
+		reflect2.Type2(et),
+		elemDecoder,
+	}
+}
@@ -0,0 +1,159 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/implementation_test.go                          |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestShape interface {
+	Area() float64
+}
+
+type TestCircle struct {
+	R float64
+}
+
+func (c *TestCircle) Area() float64 {
+	return 3 * c.R * c.R
+}
+
+type TestSquare struct {
+	Kind string `hprose:"kind"`
+	A    float64
+}
+
+func (s TestSquare) Area() float64 {
+	return s.A * s.A
+}
+
+type TestDrawing struct {
+	Shapes []TestShape
+	Main   TestShape
+	Ptr    *TestShape
+}
+
+func TestDecodeImplementationByClassName(t *testing.T) {
+	RegisterImplementation((*TestShape)(nil), "circle", (*TestCircle)(nil))
+	RegisterImplementation((*TestShape)(nil), "square", TestSquare{})
+	enc := NewEncoder(nil)
+	enc.Registry = NewTypeRegistry(ShortName, false)
+	enc.Registry.Register((*TestCircle)(nil), "circle")
+	enc.Registry.Register((*TestSquare)(nil), "square")
+	var shape TestShape = &TestCircle{1}
+	enc.Encode(TestDrawing{
+		Shapes: []TestShape{&TestCircle{2}, TestSquare{A: 3}},
+		Main:   TestSquare{A: 4},
+		Ptr:    &shape,
+	})
+	var drawing TestDrawing
+	dec := NewDecoder(enc.Bytes())
+	dec.Decode(&drawing)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []TestShape{&TestCircle{2}, TestSquare{A: 3}}, drawing.Shapes)
+	assert.Equal(t, TestSquare{A: 4}, drawing.Main)
+	assert.Equal(t, &TestCircle{1}, *drawing.Ptr)
+}
+
+func TestDecodeImplementationByDiscriminator(t *testing.T) {
+	type TestImplDrawing struct {
+		Main TestShape
+	}
+	RegisterImplementation((*TestShape)(nil), "squareKind", TestSquare{})
+	RegisterDiscriminator((*TestShape)(nil), "kind")
+	enc := NewEncoder(nil)
+	enc.Encode(map[string]interface{}{
+		"main": map[string]interface{}{"kind": "squareKind", "a": 2},
+	})
+	var drawing TestImplDrawing
+	dec := NewDecoder(enc.Bytes())
+	dec.Decode(&drawing)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestSquare{Kind: "squareKind", A: 2}, drawing.Main)
+
+	var shape TestShape
+	dec = NewDecoder([]byte(`c7"Unknown"2{s4"kind"s1"a"}o0{s10"squareKind"3}`))
+	dec.Decode(&shape)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestSquare{Kind: "squareKind", A: 3}, shape)
+}
+
+func TestDecodeImplementationError(t *testing.T) {
+	var shape TestShape
+	dec := NewDecoder([]byte(`m1{s1"a"1}`))
+	dec.Decode(&shape)
+	assert.EqualError(t, dec.Error, "hprose/encoding: can not cast map[string]interface {} to encoding.TestShape")
+	dec = NewDecoder([]byte(`s5"hello"`))
+	dec.Decode(&shape)
+	assert.EqualError(t, dec.Error, "hprose/encoding: can not cast string to encoding.TestShape")
+	shape = &TestCircle{}
+	dec = NewDecoder([]byte(`n`))
+	dec.Decode(&shape)
+	assert.NoError(t, dec.Error)
+	assert.Nil(t, shape)
+	assert.Panics(t, func() {
+		RegisterImplementation((*TestShape)(nil), "circle", TestCircle{})
+	})
+}
+
+func TestDecodeImplementationReference(t *testing.T) {
+	RegisterImplementation((*TestShape)(nil), "squareKind", TestSquare{})
+	RegisterDiscriminator((*TestShape)(nil), "kind")
+	m := map[string]interface{}{"kind": "squareKind", "a": 2}
+	data := encodeWithReference(t, []interface{}{&m, &m})
+	var shapes []TestShape
+	dec := NewDecoder(data).Simple(false)
+	dec.Decode(&shapes)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []TestShape{TestSquare{Kind: "squareKind", A: 2}, TestSquare{Kind: "squareKind", A: 2}}, shapes)
+}
+
+type TestLabeled struct {
+	Kind  string `hprose:"kind"`
+	Label *TestNode
+}
+
+func (l *TestLabeled) Area() float64 {
+	return 0
+}
+
+func TestCodecImplementation(t *testing.T) {
+	type Source struct {
+		Node  *TestNode
+		Shape map[string]interface{}
+	}
+	type Holder struct {
+		Node  *TestNode
+		Shape TestShape
+	}
+	c := Config{}.Froze()
+	c.RegisterImplementation((*TestShape)(nil), "labeled", (*TestLabeled)(nil))
+	c.RegisterDiscriminator((*TestShape)(nil), "kind")
+	node := &TestNode{Name: "a"}
+	data := encodeWithReference(t, Source{node, map[string]interface{}{"label": node, "kind": "labeled"}})
+
+	var holder Holder
+	dec := c.NewDecoderFromReader(bytes.NewReader(data), 32).Exact(true).Simple(false)
+	dec.Decode(&holder)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, node, holder.Node)
+	assert.True(t, holder.Node == holder.Shape.(*TestLabeled).Label)
+
+	dec = NewDecoder(data).Simple(false)
+	dec.Decode(&holder)
+	assert.EqualError(t, dec.Error, "hprose/encoding: can not cast map[string]interface {} to encoding.TestShape")
+}
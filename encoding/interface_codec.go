@@ -0,0 +1,174 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/interface_codec.go                              |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+type ifaceEncoder struct {
+	t      reflect.Type
+	valenc ValueEncoder
+}
+
+type ifaceDecoder struct {
+	t      reflect.Type
+	valdec ValueDecoder
+}
+
+type cachedIfaceEncoder struct {
+	gen    uint64
+	valenc ValueEncoder
+}
+
+type cachedIfaceDecoder struct {
+	gen    uint64
+	valdec ValueDecoder
+}
+
+// interfaceCodecs holds the value encoders and decoders registered for the
+// interface types, and caches the one resolved for each concrete type.
+type interfaceCodecs struct {
+	lock         sync.RWMutex
+	encoders     []ifaceEncoder
+	decoders     []ifaceDecoder
+	encoderCache sync.Map
+	decoderCache sync.Map
+}
+
+// interfaceGeneration is increased whenever an interface encoder or decoder
+// is registered to any codec, the cached resolutions of the older generations
+// are resolved again. It is 0 until the first registration.
+var interfaceGeneration atomic.Uint64
+
+func checkInterfaceType(t reflect.Type) {
+	if t == nil || t.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("hprose/encoding: invalid interface type: %v", t))
+	}
+}
+
+// implements reports whether the concrete type t or *t implements the
+// interface type it. The pointers and interfaces are not concrete types.
+func implements(t reflect.Type, it reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return false
+	}
+	return t.Implements(it) || reflect.PtrTo(t).Implements(it)
+}
+
+// RegisterInterfaceEncoder registers valenc for the concrete types which
+// implement the interface type t. See Codec.RegisterInterfaceEncoder.
+func RegisterInterfaceEncoder(t reflect.Type, valenc ValueEncoder) {
+	DefaultCodec.RegisterInterfaceEncoder(t, valenc)
+}
+
+// RegisterInterfaceDecoder registers valdec for the concrete types which
+// implement the interface type t. See Codec.RegisterInterfaceDecoder.
+func RegisterInterfaceDecoder(t reflect.Type, valdec ValueDecoder) {
+	DefaultCodec.RegisterInterfaceDecoder(t, valdec)
+}
+
+// RegisterInterfaceEncoder registers valenc to the codec for the concrete
+// types T which implement the interface type t, or whose *T implement it.
+// It is used when T has no value encoder registered for itself, and it is
+// called with a T or a *T. If several interfaces match T, the last registered
+// one wins.
+func (c *Codec) RegisterInterfaceEncoder(t reflect.Type, valenc ValueEncoder) {
+	checkInterfaceType(t)
+	c.interfaces.lock.Lock()
+	c.interfaces.encoders = append(c.interfaces.encoders, ifaceEncoder{t, valenc})
+	c.interfaces.lock.Unlock()
+	interfaceGeneration.Add(1)
+	if c != DefaultCodec {
+		c.custom.Store(true)
+	}
+}
+
+// RegisterInterfaceDecoder registers valdec to the codec for the concrete
+// types T which implement the interface type t, or whose *T implement it.
+// It is used when T has no value decoder registered for itself, and its
+// Decode is called with a *T. If several interfaces match T, the last
+// registered one wins.
+func (c *Codec) RegisterInterfaceDecoder(t reflect.Type, valdec ValueDecoder) {
+	checkInterfaceType(t)
+	c.interfaces.lock.Lock()
+	c.interfaces.decoders = append(c.interfaces.decoders, ifaceDecoder{t, valdec})
+	c.interfaces.lock.Unlock()
+	interfaceGeneration.Add(1)
+	if c != DefaultCodec {
+		c.custom.Store(true)
+	}
+}
+
+// interfaceEncoder returns the interface encoder of the concrete type t
+// registered to the codec, or to DefaultCodec.
+func (c *Codec) interfaceEncoder(t reflect.Type) ValueEncoder {
+	gen := interfaceGeneration.Load()
+	if gen == 0 {
+		return nil
+	}
+	if cached, ok := c.interfaces.encoderCache.Load(t); ok && cached.(cachedIfaceEncoder).gen == gen {
+		return cached.(cachedIfaceEncoder).valenc
+	}
+	valenc := c.interfaces.findEncoder(t)
+	if valenc == nil && c != DefaultCodec {
+		valenc = DefaultCodec.interfaces.findEncoder(t)
+	}
+	c.interfaces.encoderCache.Store(t, cachedIfaceEncoder{gen, valenc})
+	return valenc
+}
+
+// interfaceDecoder returns the interface decoder of the concrete type t
+// registered to the codec, or to DefaultCodec.
+func (c *Codec) interfaceDecoder(t reflect.Type) ValueDecoder {
+	gen := interfaceGeneration.Load()
+	if gen == 0 {
+		return nil
+	}
+	if cached, ok := c.interfaces.decoderCache.Load(t); ok && cached.(cachedIfaceDecoder).gen == gen {
+		return cached.(cachedIfaceDecoder).valdec
+	}
+	valdec := c.interfaces.findDecoder(t)
+	if valdec == nil && c != DefaultCodec {
+		valdec = DefaultCodec.interfaces.findDecoder(t)
+	}
+	c.interfaces.decoderCache.Store(t, cachedIfaceDecoder{gen, valdec})
+	return valdec
+}
+
+func (ic *interfaceCodecs) findEncoder(t reflect.Type) ValueEncoder {
+	ic.lock.RLock()
+	defer ic.lock.RUnlock()
+	for i := len(ic.encoders) - 1; i >= 0; i-- {
+		if implements(t, ic.encoders[i].t) {
+			return ic.encoders[i].valenc
+		}
+	}
+	return nil
+}
+
+func (ic *interfaceCodecs) findDecoder(t reflect.Type) ValueDecoder {
+	ic.lock.RLock()
+	defer ic.lock.RUnlock()
+	for i := len(ic.decoders) - 1; i >= 0; i-- {
+		if implements(t, ic.decoders[i].t) {
+			return ic.decoders[i].valdec
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/interface_codec_test.go                         |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestMoney interface {
+	Currency() string
+	Cents() int64
+}
+
+type TestUSD int64
+
+func (m TestUSD) Currency() string { return "USD" }
+func (m TestUSD) Cents() int64     { return int64(m) }
+
+type TestEUR struct {
+	Amount int64
+}
+
+func (m *TestEUR) Currency() string { return "EUR" }
+func (m *TestEUR) Cents() int64     { return m.Amount }
+
+var testMoneyType = reflect.TypeOf((*TestMoney)(nil)).Elem()
+
+type testMoneyEncoder struct{}
+
+func (testMoneyEncoder) Encode(enc *Encoder, v interface{}) {
+	testMoneyEncoder{}.Write(enc, v)
+}
+
+func (testMoneyEncoder) Write(enc *Encoder, v interface{}) {
+	m, ok := v.(TestMoney)
+	if !ok {
+		m = reflect.New(reflect.TypeOf(v)).Interface().(TestMoney)
+		reflect.ValueOf(m).Elem().Set(reflect.ValueOf(v))
+	}
+	enc.EncodeString(fmt.Sprintf("%d %s", m.Cents(), m.Currency()))
+}
+
+type testMoneyDecoder struct{}
+
+func (testMoneyDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	var s string
+	dec.decode(&s, tag)
+	var cents int64
+	var currency string
+	fmt.Sscanf(s, "%d %s", &cents, &currency)
+	switch p := p.(type) {
+	case *TestUSD:
+		*p = TestUSD(cents)
+	case *TestEUR:
+		p.Amount = cents
+	}
+}
+
+func (testMoneyDecoder) Type() reflect.Type {
+	return testMoneyType
+}
+
+type TestMoneyStruct struct {
+	Price TestUSD
+	Cost  *TestUSD
+	Fee   TestEUR
+	Fees  []TestEUR
+}
+
+func TestInterfaceEncoder(t *testing.T) {
+	codec := Config{}.Froze()
+	codec.RegisterInterfaceEncoder(testMoneyType, testMoneyEncoder{})
+	cost := TestUSD(50)
+	data, err := codec.Append(nil, TestMoneyStruct{TestUSD(123), &cost, TestEUR{45}, []TestEUR{{6}}})
+	assert.NoError(t, err)
+	assert.Equal(t, `c15"TestMoneyStruct"4{s5"price"s4"cost"s3"fee"s4"fees"}`+
+		`o0{s7"123 USD"s6"50 USD"s6"45 EUR"a1{s5"6 EUR"}}`, string(data))
+
+	data, err = codec.Append(nil, []interface{}{TestUSD(1), &TestEUR{2}, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, `a3{s5"1 USD"s5"2 EUR"3}`, string(data))
+
+	data, err = Append(nil, TestUSD(1))
+	assert.NoError(t, err)
+	assert.Equal(t, `1`, string(data))
+
+	codec.RegisterValueEncoder(TestUSD(0), testUSDEncoder{})
+	data, err = codec.Append(nil, []interface{}{TestUSD(1), TestEUR{2}})
+	assert.NoError(t, err)
+	assert.Equal(t, `a2{s2"$1"s5"2 EUR"}`, string(data))
+}
+
+type testUSDEncoder struct{}
+
+func (testUSDEncoder) Encode(enc *Encoder, v interface{}) {
+	testUSDEncoder{}.Write(enc, v)
+}
+
+func (testUSDEncoder) Write(enc *Encoder, v interface{}) {
+	if p, ok := v.(*TestUSD); ok {
+		v = *p
+	}
+	enc.EncodeString(fmt.Sprintf("$%d", v.(TestUSD)))
+}
+
+func TestInterfaceDecoder(t *testing.T) {
+	codec := Config{}.Froze()
+	codec.RegisterInterfaceDecoder(testMoneyType, testMoneyDecoder{})
+	var v TestMoneyStruct
+	dec := codec.NewDecoder([]byte(`m4{s5"price"s7"123 USD"s4"cost"s6"50 USD"s3"fee"s6"45 EUR"s4"fees"a1{s5"6 EUR"}}`))
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestUSD(123), v.Price)
+	assert.Equal(t, TestUSD(50), *v.Cost)
+	assert.Equal(t, TestEUR{45}, v.Fee)
+	assert.Equal(t, []TestEUR{{6}}, v.Fees)
+
+	var usd *TestUSD
+	dec = codec.NewDecoder([]byte(`s5"7 USD"`))
+	dec.Decode(&usd)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestUSD(7), *usd)
+
+	var n TestUSD
+	dec = NewDecoder([]byte(`s5"7 USD"`))
+	dec.Decode(&n)
+	assert.Error(t, dec.Error)
+}
+
+func TestInvalidInterfaceType(t *testing.T) {
+	assert.PanicsWithValue(t, "hprose/encoding: invalid interface type: encoding.TestUSD", func() {
+		RegisterInterfaceEncoder(reflect.TypeOf(TestUSD(0)), testMoneyEncoder{})
+	})
+	assert.PanicsWithValue(t, "hprose/encoding: invalid interface type: <nil>", func() {
+		RegisterInterfaceDecoder(nil, testMoneyDecoder{})
+	})
+}
+
+type TestYen int64
+
+func (m TestYen) Currency() string { return "JPY" }
+func (m TestYen) Cents() int64     { return int64(m) * 100 }
+func (m TestYen) Yen()             {}
+
+func TestDefaultInterfaceEncoder(t *testing.T) {
+	type TestYenMoney interface {
+		Yen()
+	}
+	RegisterInterfaceEncoder(reflect.TypeOf((*TestYenMoney)(nil)).Elem(), testMoneyEncoder{})
+	sb := &strings.Builder{}
+	enc := Config{}.Froze().NewEncoder(sb)
+	enc.Encode(TestYen(5))
+	assert.Equal(t, `s7"500 JPY"`, sb.String())
+	data, err := Append(nil, map[string]TestYen{"a": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `m1{uas7"100 JPY"}`, string(data))
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/interface_decoder.go                            |
 |                                                          |
-| LastModified: Jun 27, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -108,6 +108,13 @@ func (dec *Decoder) decodeInterface(t reflect.Type, tag byte) interface{} {
 		}
 	case TagObject:
 		return dec.ReadObject()
+	case TagRef:
+		var v interface{}
+		dec.decodeReference(&v)
+		return v
+	case TagClass:
+		dec.ReadStruct()
+		return dec.decodeInterface(t, dec.NextByte())
 	}
 	if dec.Error == nil {
 		dec.Error = DecodeError(fmt.Sprintf("hprose/encoding: invalid tag '%s'(0x%x)", string(tag), tag))
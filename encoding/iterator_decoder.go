@@ -0,0 +1,68 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/iterator_decoder.go                             |
+|                                                          |
+| LastModified: Oct 18, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// decodeEach decodes a list element by element without keeping the list,
+// so the list itself can not be referenced later.
+func (dec *Decoder) decodeEach(et reflect.Type, f func(v reflect.Value)) {
+	tag := dec.NextByte()
+	switch tag {
+	case TagNull, TagEmpty:
+	case TagClass:
+		dec.ReadStruct()
+		dec.decodeEach(et, f)
+	case TagList:
+		count := dec.ReadInt()
+		dec.AddReference(nil)
+		for i := 0; i < count && dec.Error == nil; i++ {
+			p := reflect.New(et)
+			dec.Decode(p.Interface())
+			f(p.Elem())
+		}
+		dec.Skip()
+	default:
+		dec.decodeError(reflect.SliceOf(et), tag)
+	}
+}
+
+// DecodeEach decodes a list element by element, f must be a function like
+// func(T), which is called with each element decoded as T.
+func (dec *Decoder) DecodeEach(f interface{}) {
+	fn := reflect.ValueOf(f)
+	t := fn.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 {
+		panic(fmt.Sprintf("hprose/encoding: invalid function: %s", t.String()))
+	}
+	args := make([]reflect.Value, 1)
+	dec.decodeEach(t.In(0), func(v reflect.Value) {
+		args[0] = v
+		fn.Call(args)
+	})
+}
+
+// DecodeChan decodes a list element by element, and sends each element to
+// ch, which must be a chan T or chan<- T. ch is not closed after decoding.
+func (dec *Decoder) DecodeChan(ch interface{}) {
+	c := reflect.ValueOf(ch)
+	t := c.Type()
+	if t.Kind() != reflect.Chan || t.ChanDir()&reflect.SendDir == 0 {
+		panic(fmt.Sprintf("hprose/encoding: invalid channel: %s", t.String()))
+	}
+	dec.decodeEach(t.Elem(), c.Send)
+}
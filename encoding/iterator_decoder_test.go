@@ -0,0 +1,57 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/iterator_decoder_test.go                        |
+|                                                          |
+| LastModified: Oct 18, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeEach(t *testing.T) {
+	var result []string
+	dec := NewDecoder([]byte(`a3{s5"hello"s5"world"s5"hello"}s5"hello"`))
+	dec.DecodeEach(func(s string) {
+		result = append(result, s)
+	})
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []string{"hello", "world", "hello"}, result)
+	var s string
+	dec.Decode(&s)
+	assert.Equal(t, "hello", s)
+	dec = NewDecoder([]byte(`s5"hello"`))
+	dec.DecodeEach(func(i int) {})
+	assert.EqualError(t, dec.Error, "hprose/encoding: can not cast string to []int")
+	assert.Panics(t, func() {
+		dec.DecodeEach(func() {})
+	})
+}
+
+func TestDecodeChan(t *testing.T) {
+	ch := make(chan int)
+	dec := NewDecoder([]byte(`a3{123}`))
+	go func() {
+		dec.DecodeChan(ch)
+		close(ch)
+	}()
+	var result []int
+	for i := range ch {
+		result = append(result, i)
+	}
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []int{1, 2, 3}, result)
+	assert.Panics(t, func() {
+		dec.DecodeChan(make(<-chan int))
+	})
+}
@@ -0,0 +1,78 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/iterator_encoder.go                             |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"reflect"
+)
+
+// writeChan drains the channel v into a list until it is closed.
+func (enc *Encoder) writeChan(v interface{}) {
+	ch := reflect.ValueOf(v)
+	enc.AddReferenceCount(1)
+	enc.WriteListHead(-1)
+	n := 0
+	for enc.Error == nil {
+		e, ok := ch.Recv()
+		if !ok {
+			break
+		}
+		enc.encode(e.Interface())
+		n++
+	}
+	enc.WriteListFoot(n)
+}
+
+// writeSeq drains the iterator v into a list or map. v must be a function
+// like iter.Seq[T] (func(yield func(T) bool)) or iter.Seq2[K, V]
+// (func(yield func(K, V) bool)), otherwise writeSeq returns false.
+func (enc *Encoder) writeSeq(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	if t.NumIn() != 1 || t.NumOut() != 0 {
+		return false
+	}
+	yt := t.In(0)
+	if yt.Kind() != reflect.Func || yt.NumOut() != 1 || yt.Out(0).Kind() != reflect.Bool {
+		return false
+	}
+	n := 0
+	var next, stop []reflect.Value
+	next = append(next, reflect.ValueOf(true).Convert(yt.Out(0)))
+	stop = append(stop, reflect.ValueOf(false).Convert(yt.Out(0)))
+	yield := func(args []reflect.Value) []reflect.Value {
+		for _, arg := range args {
+			enc.encode(arg.Interface())
+		}
+		n++
+		if enc.Error != nil {
+			return stop
+		}
+		return next
+	}
+	switch yt.NumIn() {
+	case 1:
+		enc.AddReferenceCount(1)
+		enc.WriteListHead(-1)
+		reflect.ValueOf(v).Call([]reflect.Value{reflect.MakeFunc(yt, yield)})
+		enc.WriteListFoot(n)
+	case 2:
+		enc.AddReferenceCount(1)
+		enc.WriteMapHead(-1)
+		reflect.ValueOf(v).Call([]reflect.Value{reflect.MakeFunc(yt, yield)})
+		enc.WriteMapFoot(n)
+	default:
+		return false
+	}
+	return true
+}
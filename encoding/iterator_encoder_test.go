@@ -0,0 +1,59 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/iterator_encoder_test.go                        |
+|                                                          |
+| LastModified: Oct 18, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeChan(t *testing.T) {
+	ch := make(chan string, 3)
+	ch <- "hello"
+	ch <- "world"
+	ch <- "hello"
+	close(ch)
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	assert.NoError(t, enc.Encode([]interface{}{(<-chan string)(ch), "hello"}))
+	assert.Equal(t, `a2{a3{s5"hello"s5"world"r2;}r2;}`, sb.String())
+	sb.Reset()
+	assert.NoError(t, enc.Encode((chan int)(nil)))
+	assert.Equal(t, `n`, sb.String())
+	assert.Error(t, enc.Encode(make(chan<- int)))
+}
+
+func TestEncodeSeq(t *testing.T) {
+	seq := func(yield func(int) bool) {
+		for i := 1; i <= 3; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	seq2 := func(yield func(string, int) bool) {
+		_ = yield("a", 1) && yield("b", 2)
+	}
+	enc := NewEncoder(nil)
+	assert.NoError(t, enc.Encode(seq))
+	assert.NoError(t, enc.Encode(seq2))
+	assert.Equal(t, `a3{123}m2{ua1ub2}`, enc.String())
+	empty := func(yield func(int) bool) {}
+	enc = NewEncoder(nil)
+	assert.NoError(t, enc.Encode(empty))
+	assert.Equal(t, `a{}`, enc.String())
+	assert.Error(t, enc.Encode(func() {}))
+}
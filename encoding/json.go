@@ -0,0 +1,815 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/json.go                                         |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"encoding/base64"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// JSON mapping of hprose values:
+//
+//	null, true, false       <-> null, true, false
+//	integer, long, double   <-> number (digits are kept verbatim)
+//	NaN, +Inf, -Inf         <-> {"$float":"NaN"}, {"$float":"Infinity"}, {"$float":"-Infinity"}
+//	empty, char, string     <-> string
+//	bytes                   <-> {"$bytes":"<base64>"}
+//	date, time              <-> {"$date":"2006-01-02T15:04:05.999999999Z"}, local times have no zone
+//	guid                    <-> {"$guid":"xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"}
+//	list                    <-> array
+//	map                     <-> object, non-string keys are converted to strings
+//	object                  <-> {"$class":"Name", "field": value, ...}
+//	reference               <-> expanded value or {"$ref":n}
+//	error                   <-> {"$error":"message"}
+//
+// An object whose first member is one of the names above is treated as the
+// corresponding hprose value by FromJSON. Integers out of the int32 range are
+// written as hprose long, numbers with fraction or exponent as hprose double.
+//
+// The n in {"$ref":n} is the index of the referenced value in the JSON output,
+// counted in the order FromJSON assigns hprose references, so the result of
+// ToJSON can always be transcoded back by FromJSON. References to a list, map
+// or object that is not finished yet (cyclic references) are always written
+// as {"$ref":n}.
+
+const (
+	jsonRef   = "$ref"
+	jsonClass = "$class"
+	jsonDate  = "$date"
+	jsonGUID  = "$guid"
+	jsonBytes = "$bytes"
+	jsonFloat = "$float"
+	jsonError = "$error"
+)
+
+const (
+	jsonLocalTimeFormat = "2006-01-02T15:04:05.999999999"
+	jsonUTCTimeFormat   = "2006-01-02T15:04:05.999999999Z07:00"
+)
+
+// JSONTranscoder transcodes data between hprose and JSON.
+type JSONTranscoder struct {
+	// KeepReference writes hprose references as {"$ref":n} instead of expanding them.
+	KeepReference bool
+}
+
+// ToJSON transcodes hprose data to JSON, references are expanded.
+// Multiple hprose values are transcoded to newline separated JSON values.
+func ToJSON(data []byte) ([]byte, error) {
+	return JSONTranscoder{}.ToJSON(data)
+}
+
+// FromJSON transcodes JSON data to hprose.
+// Multiple JSON values are transcoded to back-to-back hprose values.
+func FromJSON(data []byte) ([]byte, error) {
+	return JSONTranscoder{}.FromJSON(data)
+}
+
+// ToJSON transcodes hprose data to JSON.
+func (t JSONTranscoder) ToJSON(data []byte) ([]byte, error) {
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, len(data)*2)
+	if err := t.toJSON(stream, NewDecoder(data), false); err != nil {
+		return nil, err
+	}
+	return stream.Buffer(), nil
+}
+
+// FromJSON transcodes JSON data to hprose.
+func (t JSONTranscoder) FromJSON(data []byte) ([]byte, error) {
+	enc := NewEncoder(nil).Simple(true)
+	if err := t.fromJSON(enc, jsoniter.ParseBytes(jsoniter.ConfigDefault, data), nil); err != nil {
+		return nil, err
+	}
+	return enc.buf, nil
+}
+
+// WriteJSON transcodes hprose data from r to JSON and writes it to w.
+// The output is flushed to w after each top-level value.
+func (t JSONTranscoder) WriteJSON(w io.Writer, r io.Reader) error {
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, w, defaultBufferSize)
+	return t.toJSON(stream, NewDecoderFromReader(r, defaultBufferSize), true)
+}
+
+// ReadJSON transcodes JSON data from r to hprose and writes it to w.
+// The output is written to w after each top-level value. Each top-level
+// value is buffered and read twice, once to count the list and map sizes
+// the hprose heads need up front, and once to transcode it, so the cost is
+// linear in its size regardless of its nesting.
+func (t JSONTranscoder) ReadJSON(w io.Writer, r io.Reader) error {
+	enc := NewEncoder(nil).Simple(true)
+	return t.fromJSON(enc, jsoniter.Parse(jsoniter.ConfigDefault, r, defaultBufferSize), w)
+}
+
+func (t JSONTranscoder) toJSON(stream *jsoniter.Stream, dec *Decoder, flush bool) error {
+	tr := &hproseToJSON{dec: dec, stream: stream, expand: !t.KeepReference}
+	for i := 0; ; i++ {
+		tag := dec.NextByte()
+		if dec.Error == io.EOF {
+			return nil
+		}
+		if i > 0 {
+			stream.WriteRaw("\n")
+		}
+		tr.transcode(tag)
+		if dec.Error != nil {
+			return dec.Error
+		}
+		if tr.err != nil {
+			return tr.err
+		}
+		if flush {
+			if err := tr.flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (t JSONTranscoder) fromJSON(enc *Encoder, iter *jsoniter.Iterator, w io.Writer) error {
+	tr := &jsonToHprose{enc: enc, classes: make(map[string]int)}
+	for {
+		if iter.WhatIsNext() == jsoniter.InvalidValue {
+			if iter.Error == io.EOF {
+				return nil
+			}
+			if iter.Error != nil {
+				return iter.Error
+			}
+			return DecodeError("hprose/encoding: invalid JSON value")
+		}
+		raw := iter.SkipAndReturnBytes()
+		if iter.Error != nil && iter.Error != io.EOF {
+			return iter.Error
+		}
+		tr.value(raw)
+		if tr.err != nil {
+			return tr.err
+		}
+		if w != nil {
+			if _, err := w.Write(enc.buf); err != nil {
+				return err
+			}
+			enc.buf = enc.buf[:0]
+		}
+	}
+}
+
+// jsonReference records where the JSON text of a hprose reference is.
+type jsonReference struct {
+	start  int
+	end    int    // end is -1 when the value is not finished yet.
+	data   []byte // data is the JSON text when it is no longer in the buffer.
+	index  int    // index is the reference index in JSON, -1 when it has none.
+	values int    // values is the count of JSON references in the value.
+}
+
+type jsonClassInfo struct {
+	name   string
+	names  []string
+	fields int // fields is the count of JSON references for the field names.
+}
+
+type hproseToJSON struct {
+	dec     *Decoder
+	stream  *jsoniter.Stream
+	expand  bool
+	refs    []jsonReference
+	classes []jsonClassInfo
+	defined map[string]bool
+	count   int
+	values  int
+	err     error
+}
+
+func (tr *hproseToJSON) error(msg string) {
+	if tr.err == nil {
+		tr.err = DecodeError("hprose/encoding: " + msg)
+	}
+}
+
+// flush writes the buffered JSON to the writer of stream, the text of the
+// expandable references is saved before it is removed from the buffer.
+func (tr *hproseToJSON) flush() error {
+	if tr.expand {
+		buf := tr.stream.Buffer()
+		for i := range tr.refs {
+			ref := &tr.refs[i]
+			if ref.data == nil && ref.end >= 0 {
+				ref.data = append([]byte{}, buf[ref.start:ref.end]...)
+			}
+		}
+	}
+	return tr.stream.Flush()
+}
+
+func (tr *hproseToJSON) countString(s string) {
+	if utf16Length(s) > 1 {
+		tr.count++
+		tr.values++
+	}
+}
+
+func (tr *hproseToJSON) begin() int {
+	tr.refs = append(tr.refs, jsonReference{
+		start:  len(tr.stream.Buffer()),
+		end:    -1,
+		index:  tr.count,
+		values: tr.values,
+	})
+	tr.count++
+	tr.values++
+	return len(tr.refs) - 1
+}
+
+func (tr *hproseToJSON) end(i int) {
+	ref := &tr.refs[i]
+	ref.end = len(tr.stream.Buffer())
+	ref.values = tr.values - ref.values
+}
+
+func (tr *hproseToJSON) writeSpecial(name string, value string) {
+	tr.stream.WriteObjectStart()
+	tr.stream.WriteObjectField(name)
+	tr.stream.WriteString(value)
+	tr.stream.WriteObjectEnd()
+}
+
+func (tr *hproseToJSON) writeReference(i int) {
+	if i < 0 || i >= len(tr.refs) {
+		tr.error("invalid reference " + strconv.Itoa(i))
+		return
+	}
+	ref := tr.refs[i]
+	switch {
+	case ref.data != nil && (tr.expand || ref.index < 0):
+		_, _ = tr.stream.Write(ref.data)
+	case ref.end >= 0 && (tr.expand || ref.index < 0):
+		tr.stream.SetBuffer(append(tr.stream.Buffer(), tr.stream.Buffer()[ref.start:ref.end]...))
+	default:
+		tr.stream.WriteObjectStart()
+		tr.stream.WriteObjectField(jsonRef)
+		tr.stream.WriteInt(ref.index)
+		tr.stream.WriteObjectEnd()
+		return
+	}
+	tr.count += ref.values
+	tr.values += ref.values
+}
+
+func (tr *hproseToJSON) writeTime(t time.Time) {
+	i := tr.begin()
+	if t.Location() == time.UTC {
+		tr.writeSpecial(jsonDate, t.Format(jsonUTCTimeFormat))
+	} else {
+		tr.writeSpecial(jsonDate, t.Format(jsonLocalTimeFormat))
+	}
+	tr.end(i)
+}
+
+func (tr *hproseToJSON) readClass() {
+	dec := tr.dec
+	name := dec.ReadSafeString()
+	count := dec.ReadInt()
+	names := make([]string, count)
+	fields := 0
+	for i := 0; i < count; i++ {
+		names[i] = dec.decodeString(stringType, dec.NextByte())
+		tr.refs = append(tr.refs, jsonReference{
+			end:   -1,
+			data:  []byte(strconv.Quote(names[i])),
+			index: -1,
+		})
+		if utf16Length(names[i]) > 1 {
+			fields++
+		}
+	}
+	dec.Skip()
+	tr.classes = append(tr.classes, jsonClassInfo{name, names, fields})
+}
+
+func (tr *hproseToJSON) writeObject() {
+	index := tr.dec.ReadInt()
+	if index < 0 || index >= len(tr.classes) {
+		tr.error("invalid class index " + strconv.Itoa(index))
+		return
+	}
+	class := tr.classes[index]
+	signature := classSignature(class.name, class.names)
+	if tr.defined == nil {
+		tr.defined = make(map[string]bool)
+	}
+	if !tr.defined[signature] {
+		tr.defined[signature] = true
+		tr.count += class.fields
+	}
+	i := tr.begin()
+	stream := tr.stream
+	stream.WriteObjectStart()
+	stream.WriteObjectField(jsonClass)
+	stream.WriteString(class.name)
+	for _, name := range class.names {
+		stream.WriteMore()
+		stream.WriteObjectField(name)
+		tr.transcode(tr.dec.NextByte())
+	}
+	stream.WriteObjectEnd()
+	tr.dec.Skip()
+	tr.end(i)
+}
+
+func (tr *hproseToJSON) writeList() {
+	i := tr.begin()
+	count := tr.dec.ReadInt()
+	tr.stream.WriteArrayStart()
+	for j := 0; j < count; j++ {
+		if j > 0 {
+			tr.stream.WriteMore()
+		}
+		tr.transcode(tr.dec.NextByte())
+	}
+	tr.stream.WriteArrayEnd()
+	tr.dec.Skip()
+	tr.end(i)
+}
+
+func (tr *hproseToJSON) writeKey(tag byte) {
+	stream := tr.stream
+	start := len(stream.Buffer())
+	refs, count, values := len(tr.refs), tr.count, tr.values
+	tr.transcode(tag)
+	buf := stream.Buffer()
+	if len(buf) == start {
+		return
+	}
+	if buf[start] == '"' {
+		stream.WriteRaw(":")
+		return
+	}
+	key := string(buf[start:])
+	for i := refs; i < len(tr.refs); i++ {
+		ref := &tr.refs[i]
+		if ref.data == nil && ref.end >= 0 {
+			ref.data = append([]byte{}, buf[ref.start:ref.end]...)
+		}
+		ref.index = -1
+	}
+	tr.count, tr.values = count, values
+	stream.SetBuffer(buf[:start])
+	tr.countString(key)
+	stream.WriteObjectField(key)
+}
+
+func (tr *hproseToJSON) writeMap() {
+	i := tr.begin()
+	count := tr.dec.ReadInt()
+	tr.stream.WriteObjectStart()
+	for j := 0; j < count; j++ {
+		if j > 0 {
+			tr.stream.WriteMore()
+		}
+		tr.writeKey(tr.dec.NextByte())
+		tr.transcode(tr.dec.NextByte())
+	}
+	tr.stream.WriteObjectEnd()
+	tr.dec.Skip()
+	tr.end(i)
+}
+
+func (tr *hproseToJSON) transcode(tag byte) {
+	if tr.err != nil || tr.dec.Error != nil {
+		return
+	}
+	dec := tr.dec
+	stream := tr.stream
+	if i := intDigits[tag]; i != invalidDigit {
+		stream.WriteUint64(i)
+		return
+	}
+	switch tag {
+	case TagNull:
+		stream.WriteNil()
+	case TagEmpty:
+		stream.WriteString("")
+	case TagTrue:
+		stream.WriteTrue()
+	case TagFalse:
+		stream.WriteFalse()
+	case TagInteger, TagLong, TagDouble:
+		s := strings.TrimPrefix(unsafeString(dec.UnsafeUntil(TagSemicolon)), "+")
+		if !isJSONNumber(s) {
+			tr.error("invalid number " + s)
+			return
+		}
+		stream.WriteRaw(s)
+	case TagNaN:
+		tr.writeSpecial(jsonFloat, "NaN")
+	case TagInfinity:
+		if dec.NextByte() == TagNeg {
+			tr.writeSpecial(jsonFloat, "-Infinity")
+		} else {
+			tr.writeSpecial(jsonFloat, "Infinity")
+		}
+	case TagUTF8Char:
+		stream.WriteString(dec.readUnsafeString(1))
+	case TagString:
+		i := tr.begin()
+		s := dec.ReadUnsafeString()
+		if utf16Length(s) < 2 {
+			tr.count--
+			tr.values--
+			tr.refs[i].index = -1
+		}
+		stream.WriteString(s)
+		tr.end(i)
+	case TagBytes:
+		i := tr.begin()
+		tr.writeSpecial(jsonBytes, base64.StdEncoding.EncodeToString(dec.readUnsafeBytes()))
+		tr.end(i)
+	case TagDate:
+		tr.writeTime(dec.ReadDateTime())
+	case TagTime:
+		tr.writeTime(dec.ReadTime())
+	case TagGUID:
+		i := tr.begin()
+		tr.writeSpecial(jsonGUID, dec.ReadUUID().String())
+		tr.end(i)
+	case TagList:
+		tr.writeList()
+	case TagMap:
+		tr.writeMap()
+	case TagClass:
+		tr.readClass()
+		tr.transcode(dec.NextByte())
+	case TagObject:
+		tr.writeObject()
+	case TagRef:
+		tr.writeReference(dec.ReadInt())
+	case TagError:
+		tag = dec.NextByte()
+		var s string
+		switch tag {
+		case TagString:
+			s = dec.ReadSafeString()
+		case TagBytes:
+			s = string(dec.readUnsafeBytes())
+		default:
+			s = dec.decodeString(stringType, tag)
+		}
+		tr.refs = append(tr.refs, jsonReference{end: -1, data: []byte(strconv.Quote(s)), index: -1})
+		tr.count++
+		tr.values++
+		tr.writeSpecial(jsonError, s)
+	default:
+		tr.error("invalid tag '" + string(tag) + "'(0x" + strconv.FormatUint(uint64(tag), 16) + ")")
+	}
+}
+
+// jsonShape is the shape of a JSON array or object, which is found by scan
+// before transcoding, since hprose writes the count of elements and the field
+// names of a class before them.
+type jsonShape struct {
+	kind  byte     // kind is TagList, TagMap, TagObject, or 0 for the special objects.
+	count int      // count is the count of elements or members.
+	class string   // class is the value of "$class".
+	names []string // names is the field names of the class.
+}
+
+type jsonToHprose struct {
+	enc     *Encoder
+	classes map[string]int
+	shapes  []jsonShape
+	next    int // next is the index of the shape of the next array or object.
+	refs    int // refs is the count of the hprose references assigned.
+	err     error
+}
+
+func (tr *jsonToHprose) error(msg string) {
+	if tr.err == nil {
+		tr.err = DecodeError("hprose/encoding: " + msg)
+	}
+}
+
+// value transcodes the JSON value raw. It is read twice, first to find the
+// shapes of its arrays and objects, then to transcode it, so the cost is
+// linear in its size regardless of nesting.
+func (tr *jsonToHprose) value(raw []byte) {
+	tr.shapes, tr.next = tr.shapes[:0], 0
+	tr.scan(jsoniter.ParseBytes(jsoniter.ConfigDefault, raw))
+	iter := jsoniter.ParseBytes(jsoniter.ConfigDefault, raw)
+	tr.transcode(iter)
+	if tr.err == nil && iter.Error != nil && iter.Error != io.EOF {
+		tr.err = iter.Error
+	}
+}
+
+// scan records the shapes of the arrays and objects in the JSON value in the
+// order transcode meets them.
+func (tr *jsonToHprose) scan(iter *jsoniter.Iterator) {
+	switch iter.WhatIsNext() {
+	case jsoniter.ArrayValue:
+		i := len(tr.shapes)
+		tr.shapes = append(tr.shapes, jsonShape{kind: TagList})
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			tr.shapes[i].count++
+			tr.scan(iter)
+			return true
+		})
+	case jsoniter.ObjectValue:
+		i := len(tr.shapes)
+		tr.shapes = append(tr.shapes, jsonShape{kind: TagMap})
+		first := true
+		iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+			if first {
+				first = false
+				switch field {
+				case jsonRef, jsonDate, jsonGUID, jsonBytes, jsonFloat, jsonError:
+					tr.shapes[i].kind = 0
+				case jsonClass:
+					if iter.WhatIsNext() == jsoniter.StringValue {
+						tr.shapes[i].kind, tr.shapes[i].class = TagObject, iter.ReadString()
+						return true
+					}
+				}
+			}
+			switch tr.shapes[i].kind {
+			case 0:
+				iter.Skip()
+				return true
+			case TagObject:
+				tr.shapes[i].names = append(tr.shapes[i].names, field)
+			}
+			tr.shapes[i].count++
+			tr.scan(iter)
+			return true
+		})
+	default:
+		iter.Skip()
+	}
+}
+
+func (tr *jsonToHprose) shape() jsonShape {
+	shape := tr.shapes[tr.next]
+	tr.next++
+	return shape
+}
+
+func (tr *jsonToHprose) writeString(s string) {
+	if n := utf16Length(s); n != 0 && n != 1 {
+		tr.refs++
+	}
+	tr.enc.EncodeString(s)
+}
+
+// isJSONNumber reports whether s matches the JSON number grammar
+// -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?.
+func isJSONNumber(s string) bool {
+	i, n := 0, len(s)
+	digits := func() bool {
+		j := i
+		for i < n && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i > j
+	}
+	if i < n && s[i] == '-' {
+		i++
+	}
+	switch {
+	case i < n && s[i] == '0':
+		i++
+	case !digits():
+		return false
+	}
+	if i < n && s[i] == '.' {
+		i++
+		if !digits() {
+			return false
+		}
+	}
+	if i < n && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < n && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if !digits() {
+			return false
+		}
+	}
+	return i == n
+}
+
+func (tr *jsonToHprose) writeNumber(s string) {
+	enc := tr.enc
+	if !isJSONNumber(s) {
+		tr.error("invalid number " + s)
+		return
+	}
+	if strings.ContainsAny(s, ".eE") {
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			tr.error("invalid number " + s)
+			return
+		}
+		enc.buf = append(enc.buf, TagDouble)
+		enc.buf = append(enc.buf, s...)
+		enc.buf = append(enc.buf, TagSemicolon)
+		return
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		enc.WriteInt64(i)
+		return
+	}
+	enc.buf = append(enc.buf, TagLong)
+	enc.buf = append(enc.buf, s...)
+	enc.buf = append(enc.buf, TagSemicolon)
+}
+
+func (tr *jsonToHprose) writeArray(iter *jsoniter.Iterator) {
+	tr.refs++
+	tr.enc.WriteListHead(tr.shape().count)
+	iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		tr.transcode(iter)
+		return tr.err == nil
+	})
+	tr.enc.WriteFoot()
+}
+
+func (tr *jsonToHprose) writeReference(iter *jsoniter.Iterator) {
+	if iter.WhatIsNext() != jsoniter.NumberValue {
+		tr.error("invalid reference")
+		return
+	}
+	n := iter.ReadInt64()
+	switch {
+	case iter.Error != nil && iter.Error != io.EOF:
+		tr.error("invalid reference")
+	case n < 0 || n >= int64(tr.refs):
+		tr.error("invalid reference " + strconv.FormatInt(n, 10))
+	default:
+		enc := tr.enc
+		enc.buf = append(enc.buf, TagRef)
+		enc.buf = strconv.AppendInt(enc.buf, n, 10)
+		enc.buf = append(enc.buf, TagSemicolon)
+	}
+}
+
+func (tr *jsonToHprose) writeSpecial(iter *jsoniter.Iterator, name string) {
+	if name == jsonRef {
+		tr.writeReference(iter)
+		return
+	}
+	enc := tr.enc
+	s := iter.ReadString()
+	switch name {
+	case jsonDate:
+		layout, loc := jsonLocalTimeFormat, time.Local
+		if strings.HasSuffix(s, "Z") || strings.LastIndexAny(s, "+-") > strings.IndexByte(s, 'T') {
+			layout, loc = jsonUTCTimeFormat, time.UTC
+		}
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err != nil {
+			tr.error("invalid date " + strconv.Quote(s))
+			return
+		}
+		if loc == time.UTC {
+			t = t.UTC()
+		}
+		tr.refs++
+		enc.writeTime(t)
+	case jsonGUID:
+		id, err := uuid.Parse(s)
+		if err != nil {
+			tr.error("invalid guid " + strconv.Quote(s))
+			return
+		}
+		tr.refs++
+		enc.writeUUID(id)
+	case jsonBytes:
+		data, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			tr.error("invalid bytes " + strconv.Quote(s))
+			return
+		}
+		tr.refs++
+		enc.buf = appendBytes(enc.buf, data)
+	case jsonFloat:
+		switch s {
+		case "NaN":
+			enc.buf = append(enc.buf, TagNaN)
+		case "Infinity", "+Infinity":
+			enc.buf = append(enc.buf, TagInfinity, TagPos)
+		case "-Infinity":
+			enc.buf = append(enc.buf, TagInfinity, TagNeg)
+		default:
+			tr.error("invalid float " + strconv.Quote(s))
+		}
+	case jsonError:
+		tr.refs++
+		enc.buf = append(enc.buf, TagError)
+		enc.buf = appendString(enc.buf, s, utf16Length(s))
+	}
+}
+
+func (tr *jsonToHprose) writeClass(shape jsonShape) {
+	signature := classSignature(shape.class, shape.names)
+	enc := tr.enc
+	index, ok := tr.classes[signature]
+	if !ok {
+		index = len(tr.classes)
+		tr.classes[signature] = index
+		tr.refs += len(shape.names)
+		enc.buf = append(enc.buf, TagClass)
+		enc.buf = appendName(enc.buf, shape.class, "class name")
+		if len(shape.names) > 0 {
+			enc.buf = AppendUint64(enc.buf, uint64(len(shape.names)))
+		}
+		enc.buf = append(enc.buf, TagOpenbrace)
+		for _, field := range shape.names {
+			enc.buf = append(enc.buf, TagString)
+			enc.buf = appendName(enc.buf, field, "field name")
+		}
+		enc.buf = append(enc.buf, TagClosebrace)
+	}
+	tr.refs++
+	enc.WriteObjectHead(index)
+}
+
+func (tr *jsonToHprose) writeObject(iter *jsoniter.Iterator) {
+	enc := tr.enc
+	shape := tr.shape()
+	switch shape.kind {
+	case TagMap:
+		tr.refs++
+		enc.WriteMapHead(shape.count)
+		iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+			tr.writeString(field)
+			tr.transcode(iter)
+			return tr.err == nil
+		})
+		enc.WriteFoot()
+	case TagObject:
+		tr.writeClass(shape)
+		first := true
+		iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+			if first {
+				first = false
+				iter.Skip()
+				return true
+			}
+			tr.transcode(iter)
+			return tr.err == nil
+		})
+		enc.WriteFoot()
+	default:
+		special := ""
+		iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+			if special != "" {
+				tr.error("unexpected member " + strconv.Quote(field) + " after " + strconv.Quote(special))
+				return false
+			}
+			special = field
+			tr.writeSpecial(iter, field)
+			return tr.err == nil
+		})
+	}
+}
+
+func (tr *jsonToHprose) transcode(iter *jsoniter.Iterator) {
+	if tr.err != nil {
+		return
+	}
+	enc := tr.enc
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		enc.WriteNil()
+	case jsoniter.BoolValue:
+		enc.WriteBool(iter.ReadBool())
+	case jsoniter.NumberValue:
+		tr.writeNumber(string(iter.ReadNumber()))
+	case jsoniter.StringValue:
+		tr.writeString(iter.ReadString())
+	case jsoniter.ArrayValue:
+		tr.writeArray(iter)
+	case jsoniter.ObjectValue:
+		tr.writeObject(iter)
+	default:
+		tr.error("invalid JSON value")
+	}
+}
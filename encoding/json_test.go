@@ -0,0 +1,161 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/json_test.go                                    |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToJSON(t *testing.T) {
+	id := uuid.MustParse("113a2b79-3b89-4196-b1ac-52102f482fda")
+	enc := NewEncoder(nil).Simple(false)
+	enc.Encode([]interface{}{
+		nil, true, false, 1, 123, int64(1) << 40, 3.5,
+		math.NaN(), math.Inf(1), math.Inf(-1),
+		"", "x", "hello", []byte("abc"),
+		time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC), id,
+	})
+	data, err := ToJSON(enc.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, `[null,true,false,1,123,1099511627776,3.5,`+
+		`{"$float":"NaN"},{"$float":"Infinity"},{"$float":"-Infinity"},`+
+		`"","x","hello",{"$bytes":"YWJj"},`+
+		`{"$date":"2020-01-02T03:04:05.000000006Z"},`+
+		`{"$guid":"113a2b79-3b89-4196-b1ac-52102f482fda"}]`, string(data))
+	hprose, err := FromJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, enc.String(), string(hprose))
+}
+
+func TestToJSONReference(t *testing.T) {
+	type TestJSONStruct struct {
+		Name string
+		Tags []string
+	}
+	Register((*TestJSONStruct)(nil), "TestJSONStruct")
+	s := &TestJSONStruct{"Tom", []string{"hello", "hello"}}
+	enc := NewEncoder(nil).Simple(false)
+	enc.Encode([]interface{}{"hello", s, s})
+	data, err := ToJSON(enc.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, `["hello",`+
+		`{"$class":"TestJSONStruct","name":"Tom","tags":["hello","hello"]},`+
+		`{"$class":"TestJSONStruct","name":"Tom","tags":["hello","hello"]}]`, string(data))
+	data, err = JSONTranscoder{KeepReference: true}.ToJSON(enc.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, `["hello",`+
+		`{"$class":"TestJSONStruct","name":"Tom","tags":[{"$ref":1},{"$ref":1}]},`+
+		`{"$ref":4}]`, string(data))
+	hprose, err := FromJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, enc.String(), string(hprose))
+}
+
+func TestToJSONCyclicReference(t *testing.T) {
+	data, err := ToJSON([]byte(`a2{s5"hello"a1{r0;}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `["hello",[{"$ref":0}]]`, string(data))
+	hprose, err := FromJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `a2{s5"hello"a1{r0;}}`, string(hprose))
+}
+
+func TestToJSONMapKey(t *testing.T) {
+	data, err := ToJSON([]byte(`m2{1s5"hello"s3"key"r1;}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"1":"hello","key":"hello"}`, string(data))
+}
+
+func TestToJSONInvalidNumber(t *testing.T) {
+	for _, s := range []string{`dinf;`, `dInfinity;`, `d0x1p2;`, `d.5;`, `d5.;`, `i1_0;`, `l01;`, `d1e;`} {
+		_, err := ToJSON([]byte(s))
+		assert.Error(t, err, s)
+	}
+	data, err := ToJSON([]byte(`a3{i+5;l-0;d1.5E+10;}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `[5,-0,1.5E+10]`, string(data))
+}
+
+func TestIsJSONNumber(t *testing.T) {
+	for _, s := range []string{"0", "-0", "12", "1.5", "0.25e3", "1E-7", "-9e+2"} {
+		assert.True(t, isJSONNumber(s), s)
+	}
+	for _, s := range []string{"", "-", "+1", "01", ".5", "5.", "1e", "1e+", "inf", "NaN", "0x1p2", "1_0", "1.5.2"} {
+		assert.False(t, isJSONNumber(s), s)
+	}
+}
+
+func TestToJSONMultipleValues(t *testing.T) {
+	data, err := ToJSON([]byte(`1s5"hello"a{}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n\"hello\"\n[]", string(data))
+	hprose, err := FromJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `1s5"hello"a{}`, string(hprose))
+}
+
+func TestFromJSON(t *testing.T) {
+	data, err := FromJSON([]byte(`{"a": [1, -2147483649, 12345678901234567890, 1.5e3], "b": {}, "c": []}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `m3{uaa4{1l-2147483649;l12345678901234567890;d1.5e3;}ubm{}uca{}}`, string(data))
+	data, err = FromJSON([]byte(`{"$date": "2020-01-02T03:04:05"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `D20200102T030405;`, string(data))
+	_, err = FromJSON([]byte(`{"$float": "1"}`))
+	assert.Error(t, err)
+	_, err = FromJSON([]byte(`{"$bytes": "YWJj", "a": 1}`))
+	assert.Error(t, err)
+	_, err = FromJSON([]byte(`[1e400]`))
+	assert.Error(t, err)
+}
+
+func TestFromJSONReference(t *testing.T) {
+	data, err := FromJSON([]byte(`["hello", {"$ref": 1}, {"$ref": 0}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, `a3{s5"hello"r1;r0;}`, string(data))
+	for _, s := range []string{`["hello", {"$ref": 2}]`, `[{"$ref": -1}]`, `[{"$ref": "x"}]`, `{"$ref": 0}`} {
+		_, err = FromJSON([]byte(s))
+		assert.Error(t, err, s)
+	}
+	data, err = FromJSON([]byte(`[{"$class": "A", "x": "xy"}, {"$class": "A", "x": 1}, {"$ref": 3}, {"$ref": 4}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, `a4{c1"A"1{s1"x"}o0{s2"xy"}o0{1}r3;r4;}`, string(data))
+	_, err = FromJSON([]byte(`[{"$class": "A", "x": 1}, {"$ref": 3}]`))
+	assert.Error(t, err)
+}
+
+func TestFromJSONDeepNesting(t *testing.T) {
+	const depth = 10000
+	data, err := FromJSON([]byte(strings.Repeat(`{"a":[`, depth) + strings.Repeat(`]}`, depth)))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat(`m1{uaa1{`, depth-1)+`m1{uaa{}`+strings.Repeat(`}`, depth*2-1), string(data))
+}
+
+func TestJSONTranscoderStream(t *testing.T) {
+	w := &bytes.Buffer{}
+	err := JSONTranscoder{}.WriteJSON(w, strings.NewReader(`s5"hello"a1{r0;}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "\"hello\"\n[\"hello\"]", w.String())
+	w.Reset()
+	err = JSONTranscoder{}.ReadJSON(w, strings.NewReader(`"hello" ["hello"]`))
+	assert.NoError(t, err)
+	assert.Equal(t, `s5"hello"a1{s5"hello"}`, w.String())
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/list_encoder.go                                 |
 |                                                          |
-| LastModified: Apr 12, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
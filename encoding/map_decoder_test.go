@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/map_decoder_test.go                             |
 |                                                          |
-| LastModified: Jun 27, 2020                               |
+| LastModified: Oct 18, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -7184,3 +7184,49 @@ func BenchmarkJsonDecodeIntIntMap(b *testing.B) {
 		dec.Decode(&m)
 	}
 }
+
+func TestDecodeGenericMap(t *testing.T) {
+	sb := new(strings.Builder)
+	enc := NewEncoder(sb)
+	enc.Encode(map[int64]string{1: "one", 2: "two"})
+	enc.Encode(map[string][]int{"a": {1, 2}, "b": {3, 4}})
+	enc.Encode([]string{"x", "y"})
+	dec := NewDecoder(([]byte)(sb.String()))
+	var m1 map[int64]string
+	dec.Decode(&m1)
+	assert.Equal(t, map[int64]string{1: "one", 2: "two"}, m1)
+	var m2 map[string][]int
+	dec.Decode(&m2)
+	assert.Equal(t, map[string][]int{"a": {1, 2}, "b": {3, 4}}, m2)
+	var m3 map[int]string
+	dec.Decode(&m3)
+	assert.Equal(t, map[int]string{0: "x", 1: "y"}, m3)
+	assert.NoError(t, dec.Error)
+}
+
+func benchmarkDecodeMap(b *testing.B, m interface{}, p interface{}) {
+	enc := NewEncoder(nil)
+	enc.Encode(m)
+	data := enc.Bytes()
+	dec := NewDecoder(nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dec.ResetBytes(data)
+		dec.Decode(p)
+	}
+}
+
+func BenchmarkDecodeStringIntMap(b *testing.B) {
+	var m map[string]int
+	benchmarkDecodeMap(b, map[string]int{"one": 1, "two": 2, "three": 3, "four": 4, "five": 5}, &m)
+}
+
+func BenchmarkDecodeInt64StringMap(b *testing.B) {
+	var m map[int64]string
+	benchmarkDecodeMap(b, map[int64]string{1: "one", 2: "two", 3: "three", 4: "four", 5: "five"}, &m)
+}
+
+func BenchmarkDecodeStringSliceMap(b *testing.B) {
+	var m map[string][]string
+	benchmarkDecodeMap(b, map[string][]string{"a": {"one", "two"}, "b": {"three"}, "c": {"four", "five"}}, &m)
+}
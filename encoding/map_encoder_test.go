@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/map_encoder_test.go                             |
 |                                                          |
-| LastModified: Mar 20, 2020                               |
+| LastModified: Oct 18, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -1072,3 +1072,49 @@ func TestEncodeMap(t *testing.T) {
 	sb.Reset()
 
 }
+
+func TestEncodeGenericMap(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	assert.NoError(t, enc.Encode(map[int64]string{1: "one"}))
+	assert.NoError(t, enc.Encode(map[string][]string{"a": {"x", "y"}}))
+	assert.NoError(t, enc.Encode(map[string][]int{"a": nil}))
+	assert.NoError(t, enc.Encode(map[uintptr]complex128{1: 2}))
+	assert.NoError(t, enc.Encode(map[string][]byte{"a": []byte("b")}))
+	assert.Equal(t, `m1{1s3"one"}m1{uaa2{uxuy}}m1{uan}m1{1d2;}m1{uab1"b"}`, sb.String())
+}
+
+func TestEncodeOtherMap(t *testing.T) {
+	type Key struct {
+		A int
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	assert.NoError(t, enc.Encode(map[Key]*big.Int{{1}: big.NewInt(2)}))
+	assert.Equal(t, `m1{c3"Key"1{s1"a"}o0{1}l2;}`, sb.String())
+}
+
+func benchmarkEncodeMap(b *testing.B, m interface{}) {
+	enc := NewEncoder(nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc.Encode(m)
+		enc.ResetBuffer(enc.Bytes()[:0])
+	}
+}
+
+func BenchmarkEncodeStringIntMap(b *testing.B) {
+	benchmarkEncodeMap(b, map[string]int{"one": 1, "two": 2, "three": 3, "four": 4, "five": 5})
+}
+
+func BenchmarkEncodeIntStringMap(b *testing.B) {
+	benchmarkEncodeMap(b, map[int]string{1: "one", 2: "two", 3: "three", 4: "four", 5: "five"})
+}
+
+func BenchmarkEncodeInt64StringMap(b *testing.B) {
+	benchmarkEncodeMap(b, map[int64]string{1: "one", 2: "two", 3: "three", 4: "four", 5: "five"})
+}
+
+func BenchmarkEncodeStringSliceMap(b *testing.B) {
+	benchmarkEncodeMap(b, map[string][]string{"a": {"one", "two"}, "b": {"three"}, "c": {"four", "five"}})
+}
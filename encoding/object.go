@@ -0,0 +1,68 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/object.go                                       |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+// Field is a name-value pair of Object. The scalar values of the decoded
+// fields are *Value, so that they are encoded to the same values again.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Object is a hprose object with its class name and fields in order.
+// It is used to decode objects of unregistered struct types without losing
+// the class name, and is encoded to the same object again.
+type Object struct {
+	Class  string
+	Fields []Field
+}
+
+// Get returns the value of the field named name.
+func (o *Object) Get(name string) (value interface{}, ok bool) {
+	for _, field := range o.Fields {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// appendSignature appends s prefixed with its length to the signature buf,
+// so that the names containing any bytes never make the same signature.
+func appendSignature(buf []byte, s string) []byte {
+	buf = AppendUint64(buf, uint64(len(s)))
+	buf = append(buf, ':')
+	return append(buf, s...)
+}
+
+// signature returns the class name and field names as a key for class reference.
+func signature[F any](class string, fields []F, name func(*F) string) string {
+	n := len(class) + 21
+	for i := range fields {
+		n += len(name(&fields[i])) + 21
+	}
+	buf := make([]byte, 0, n)
+	buf = appendSignature(buf, class)
+	for i := range fields {
+		buf = appendSignature(buf, name(&fields[i]))
+	}
+	return unsafeString(buf)
+}
+
+// classSignature returns the signature of class with the field names.
+func classSignature(class string, names []string) string {
+	return signature(class, names, func(name *string) string {
+		return *name
+	})
+}
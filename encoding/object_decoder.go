@@ -0,0 +1,103 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/object_decoder.go                               |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"reflect"
+
+	"github.com/modern-go/reflect2"
+)
+
+// readFieldValue reads the value of a field of an Object. The scalars are
+// read as *Value, so that they are encoded to the same values again. The
+// lists are read as []interface{}, the maps as map[interface{}]interface{}
+// and the objects of unregistered types as *Object, whose items are read in
+// the same way, so that the references to them and from them are kept.
+func (dec *Decoder) readFieldValue(tag byte) interface{} {
+	switch tag {
+	case TagRef:
+		return valueOfReference(dec.ReadReference())
+	case TagClass:
+		dec.ReadStruct()
+		return dec.readFieldValue(dec.NextByte())
+	case TagList:
+		items := make([]interface{}, dec.ReadInt())
+		dec.AddReference(items)
+		for i := range items {
+			items[i] = dec.readFieldValue(dec.NextByte())
+		}
+		dec.Skip()
+		return items
+	case TagMap:
+		count := dec.ReadInt()
+		m := make(map[interface{}]interface{}, count)
+		dec.AddReference(m)
+		for i := 0; i < count; i++ {
+			k := dec.decodeInterface(interfaceType, dec.NextByte())
+			m[k] = dec.readFieldValue(dec.NextByte())
+		}
+		dec.Skip()
+		return m
+	case TagObject:
+		index, ok := dec.readClassIndex()
+		if !ok {
+			return nil
+		}
+		structInfo := dec.getStructInfo(index)
+		if structInfo.fields != nil {
+			return dec.readObject(structInfo)
+		}
+		o := &Object{}
+		dec.readObjectAsObject(structInfo, o)
+		return o
+	}
+	return dec.decodeValue(tag)
+}
+
+func (dec *Decoder) readObjectAsObject(structInfo structInfo, o *Object) {
+	dec.AddReference(o)
+	o.Class = structInfo.name
+	o.Fields = make([]Field, len(structInfo.names))
+	for i, name := range structInfo.names {
+		o.Fields[i] = Field{name, dec.readFieldValue(dec.NextByte())}
+	}
+	dec.Skip()
+}
+
+// objectDecoder is the implementation of ValueDecoder for Object.
+type objectDecoder struct{}
+
+func (objectDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	o := (*Object)(reflect2.PtrOf(p))
+	switch tag {
+	case TagEmpty:
+		*o = Object{}
+	case TagObject:
+		if index, ok := dec.readClassIndex(); ok {
+			dec.readObjectAsObject(dec.getStructInfo(index), o)
+		}
+	case TagRef:
+		dec.decodeReference(p)
+	default:
+		dec.decodeError(objectType, tag)
+	}
+}
+
+func (objectDecoder) Type() reflect.Type {
+	return objectType
+}
+
+func init() {
+	RegisterValueDecoder(objectDecoder{})
+}
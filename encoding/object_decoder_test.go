@@ -0,0 +1,123 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/object_decoder_test.go                          |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeUnregisteredObject(t *testing.T) {
+	data := `c7"Unknown"2{s4"name"s3"age"}o0{s3"Tom"i18;}`
+	dec := NewDecoder(([]byte)(data))
+	var v interface{}
+	dec.Decode(&v)
+	assert.Equal(t, map[string]interface{}{"name": "Tom", "age": 18}, v)
+
+	dec = NewDecoder(([]byte)(data))
+	dec.ObjectType = ObjectTypeObject
+	dec.Decode(&v)
+	o := v.(*Object)
+	assert.Equal(t, "Unknown", o.Class)
+	assert.Len(t, o.Fields, 2)
+	assert.Equal(t, "age", o.Fields[1].Name)
+	assert.Equal(t, NewInt(18), o.Fields[1].Value)
+	name, ok := o.Get("name")
+	assert.True(t, ok)
+	assert.Equal(t, "Tom", name.(*Value).String())
+
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	assert.NoError(t, enc.Encode(v))
+	assert.Equal(t, data, sb.String())
+}
+
+func TestDecodeObject(t *testing.T) {
+	type TestStruct struct {
+		A int
+		B string
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	assert.NoError(t, enc.Encode(TestStruct{1, "hello"}))
+	assert.NoError(t, enc.Encode(nil))
+	dec := NewDecoder(([]byte)(sb.String()))
+	var o *Object
+	dec.Decode(&o)
+	assert.Equal(t, "TestStruct", o.Class)
+	assert.Len(t, o.Fields, 2)
+	assert.Equal(t, Field{"a", NewInt(1)}, o.Fields[0])
+	assert.Equal(t, "b", o.Fields[1].Name)
+	assert.Equal(t, "hello", o.Fields[1].Value.(*Value).String())
+	dec.Decode(&o)
+	assert.Nil(t, o)
+}
+
+func TestDecodeObjectRoundTrip(t *testing.T) {
+	data := `a2{c1"A"2{s1"x"s1"y"}o0{l5;a2{d1.50;r4;}}r3;}`
+	dec := NewDecoder(([]byte)(data)).Simple(false)
+	dec.ObjectType = ObjectTypeObject
+	var v []interface{}
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	o := v[0].(*Object)
+	assert.True(t, o == v[1])
+	y, _ := o.Get("y")
+	assert.Equal(t, 1.5, y.([]interface{})[0].(*Value).Float())
+	sb := &strings.Builder{}
+	assert.NoError(t, NewEncoder(sb).Simple(false).Encode(v))
+	assert.Equal(t, data, sb.String())
+
+	dec = NewDecoder(([]byte)(`c1"A"1{s1"x"}o0{a1{r1;}}`)).Simple(false)
+	dec.ObjectType = ObjectTypeObject
+	var i interface{}
+	dec.Decode(&i)
+	assert.NoError(t, dec.Error)
+	x, _ := i.(*Object).Get("x")
+	assert.True(t, i == x.([]interface{})[0])
+}
+
+func TestDecodeObjectReferenceRoundTrip(t *testing.T) {
+	data := `a2{c1"A"1{s1"x"}o0{r0;}r1;}`
+	dec := NewDecoder(([]byte)(data)).Simple(false)
+	dec.ObjectType = ObjectTypeObject
+	var v interface{}
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	list := v.([]interface{})
+	x, _ := list[0].(*Object).Get("x")
+	assert.Equal(t, &list[0], &x.([]interface{})[0])
+	assert.Equal(t, "x", list[1])
+	sb := &strings.Builder{}
+	assert.NoError(t, NewEncoder(sb).Simple(false).Encode(v))
+	// the reference to the field name is written as the string itself.
+	assert.Equal(t, `a2{c1"A"1{s1"x"}o0{r0;}ux}`, sb.String())
+
+	data = `a2{c1"A"2{s1"x"s1"y"}o0{s5"hello"r3;}o0{r3;r4;}}`
+	dec = NewDecoder(([]byte)(data)).Simple(false)
+	dec.ObjectType = ObjectTypeObject
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	list = v.([]interface{})
+	a, b := list[0].(*Object), list[1].(*Object)
+	y, _ := a.Get("y")
+	assert.True(t, a == y)
+	x, _ = b.Get("x")
+	assert.True(t, a == x)
+	sb.Reset()
+	assert.NoError(t, NewEncoder(sb).Simple(false).Encode(v))
+	assert.Equal(t, data, sb.String())
+}
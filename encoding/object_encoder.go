@@ -0,0 +1,62 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/object_encoder.go                               |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"github.com/modern-go/reflect2"
+)
+
+// objectEncoder is the implementation of ValueEncoder for Object/*Object.
+type objectEncoder struct{}
+
+func (valenc objectEncoder) Encode(enc *Encoder, v interface{}) {
+	enc.EncodeReference(valenc, v)
+}
+
+func (objectEncoder) Write(enc *Encoder, v interface{}) {
+	o := (*Object)(reflect2.PtrOf(v))
+	r := writeClass(enc, o.Class, o.Fields, func(field *Field) string {
+		return field.Name
+	})
+	enc.SetReference(v)
+	enc.WriteObjectHead(r)
+	for i := range o.Fields {
+		enc.encode(o.Fields[i].Value)
+	}
+	enc.WriteFoot()
+}
+
+// writeClass writes the class with the names of fields if it is not written
+// yet, and returns its reference number.
+func writeClass[F any](enc *Encoder, class string, fields []F, name func(*F) string) int {
+	return enc.writeStructType(signature(class, fields, name), func() {
+		n := len(fields)
+		enc.AddReferenceCount(n)
+		enc.buf = append(enc.buf, TagClass)
+		enc.buf = appendName(enc.buf, class, "class name")
+		if n > 0 {
+			enc.buf = AppendUint64(enc.buf, uint64(n))
+		}
+		enc.buf = append(enc.buf, TagOpenbrace)
+		for i := range fields {
+			enc.buf = append(enc.buf, TagString)
+			enc.buf = appendName(enc.buf, name(&fields[i]), "field name")
+		}
+		enc.buf = append(enc.buf, TagClosebrace)
+	})
+}
+
+func init() {
+	RegisterValueEncoder((*Object)(nil), objectEncoder{})
+}
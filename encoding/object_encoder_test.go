@@ -0,0 +1,47 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/object_encoder_test.go                          |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeObject(t *testing.T) {
+	o := &Object{
+		Class: "User",
+		Fields: []Field{
+			{"name", "Tom"},
+			{"age", 18},
+		},
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	assert.NoError(t, enc.Encode(o))
+	assert.NoError(t, enc.Encode(o))
+	assert.NoError(t, enc.Encode(*o))
+	assert.NoError(t, enc.Encode(&Object{Class: "User", Fields: []Field{{"age", 20}}}))
+	assert.Equal(t, `c4"User"2{s4"name"s3"age"}o0{s3"Tom"i18;}r2;o0{r3;i18;}`+
+		`c4"User"1{s3"age"}o1{i20;}`, sb.String())
+}
+
+func TestEncodeObjectSignature(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	assert.NoError(t, enc.Encode(Object{"A\x00b", nil}))
+	assert.NoError(t, enc.Encode(Object{"A", []Field{{"b", 1}}}))
+	assert.Equal(t, "c3\"A\x00b\"{}o0{}c1\"A\"1{s1\"b\"}o1{1}", sb.String())
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/ptr_decoder.go                                  |
 |                                                          |
-| LastModified: Jun 25, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -34,6 +34,8 @@ func (valdec ptrDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 		if *ptr != nil {
 			*ptr = nil
 		}
+	case TagRef:
+		dec.decodeReference(p)
 	default:
 		if *ptr == nil {
 			*ptr = valdec.et.UnsafeNew()
@@ -46,22 +48,22 @@ func (valdec ptrDecoder) Type() reflect.Type {
 	return valdec.t.Type1()
 }
 
-func getPtrDecoder(t reflect.Type) ValueDecoder {
+func (c *Codec) getPtrDecoder(t reflect.Type) ValueDecoder {
 	et := t.Elem()
-	if elemDecoder := getValueDecoder(et); elemDecoder != nil {
+	if elemDecoder := c.getValueDecoder(et); elemDecoder != nil {
 		return ptrDecoder{
 			reflect2.Type2(t).(*reflect2.UnsafePtrType),
 			reflect2.Type2(et),
 			elemDecoder,
 		}
 	}
-	return ptrDecoderFactories[et.Kind()](t)
+	return ptrDecoderFactories[et.Kind()](c, t)
 }
 
-func getArrayPtrDecoder(t reflect.Type) ValueDecoder {
+func (c *Codec) getArrayPtrDecoder(t reflect.Type) ValueDecoder {
 	et := t.Elem()
-	elemDecoder := getArrayDecoder(et)
-	RegisterValueDecoder(elemDecoder)
+	elemDecoder := c.getArrayDecoder(et)
+	c.storeValueDecoder(elemDecoder)
 	return ptrDecoder{
 		reflect2.Type2(t).(*reflect2.UnsafePtrType),
 		reflect2.Type2(et),
@@ -69,10 +71,10 @@ func getArrayPtrDecoder(t reflect.Type) ValueDecoder {
 	}
 }
 
-func getMapPtrDecoder(t reflect.Type) ValueDecoder {
+func (c *Codec) getMapPtrDecoder(t reflect.Type) ValueDecoder {
 	et := t.Elem()
-	elemDecoder := getMapDecoder(et)
-	RegisterValueDecoder(elemDecoder)
+	elemDecoder := c.getMapDecoder(et)
+	c.storeValueDecoder(elemDecoder)
 	return ptrDecoder{
 		reflect2.Type2(t).(*reflect2.UnsafePtrType),
 		reflect2.Type2(et),
@@ -80,10 +82,10 @@ func getMapPtrDecoder(t reflect.Type) ValueDecoder {
 	}
 }
 
-func getPtrPtrDecoder(t reflect.Type) ValueDecoder {
+func (c *Codec) getPtrPtrDecoder(t reflect.Type) ValueDecoder {
 	et := t.Elem()
-	elemDecoder := getPtrDecoder(et)
-	RegisterValueDecoder(elemDecoder)
+	elemDecoder := c.getPtrDecoder(et)
+	c.storeValueDecoder(elemDecoder)
 	return ptrDecoder{
 		reflect2.Type2(t).(*reflect2.UnsafePtrType),
 		reflect2.Type2(et),
@@ -91,10 +93,10 @@ func getPtrPtrDecoder(t reflect.Type) ValueDecoder {
 	}
 }
 
-func getSlicePtrDecoder(t reflect.Type) ValueDecoder {
+func (c *Codec) getSlicePtrDecoder(t reflect.Type) ValueDecoder {
 	et := t.Elem()
-	elemDecoder := getSliceDecoder(et)
-	RegisterValueDecoder(elemDecoder)
+	elemDecoder := c.getSliceDecoder(et)
+	c.storeValueDecoder(elemDecoder)
 	return ptrDecoder{
 		reflect2.Type2(t).(*reflect2.UnsafePtrType),
 		reflect2.Type2(et),
@@ -102,10 +104,10 @@ func getSlicePtrDecoder(t reflect.Type) ValueDecoder {
 	}
 }
 
-func getStructPtrDecoder(t reflect.Type) ValueDecoder {
+func (c *Codec) getStructPtrDecoder(t reflect.Type) ValueDecoder {
 	et := t.Elem()
-	elemDecoder := getStructDecoder(et)
-	RegisterValueDecoder(elemDecoder)
+	elemDecoder := c.getStructDecoder(et)
+	c.storeValueDecoder(elemDecoder)
 	return ptrDecoder{
 		reflect2.Type2(t).(*reflect2.UnsafePtrType),
 		reflect2.Type2(et),
@@ -113,36 +115,36 @@ func getStructPtrDecoder(t reflect.Type) ValueDecoder {
 	}
 }
 
-var ptrDecoderFactories []func(t reflect.Type) ValueDecoder
+var ptrDecoderFactories []func(c *Codec, t reflect.Type) ValueDecoder
 
 func init() {
-	ptrDecoderFactories = []func(t reflect.Type) ValueDecoder{
+	ptrDecoderFactories = []func(c *Codec, t reflect.Type) ValueDecoder{
 		reflect.Invalid:       invalidDecoder,
-		reflect.Bool:          func(t reflect.Type) ValueDecoder { return boolPtrDecoder{t} },
-		reflect.Int:           func(t reflect.Type) ValueDecoder { return intPtrDecoder{t} },
-		reflect.Int8:          func(t reflect.Type) ValueDecoder { return int8PtrDecoder{t} },
-		reflect.Int16:         func(t reflect.Type) ValueDecoder { return int16PtrDecoder{t} },
-		reflect.Int32:         func(t reflect.Type) ValueDecoder { return int32PtrDecoder{t} },
-		reflect.Int64:         func(t reflect.Type) ValueDecoder { return int64PtrDecoder{t} },
-		reflect.Uint:          func(t reflect.Type) ValueDecoder { return uintPtrDecoder{t} },
-		reflect.Uint8:         func(t reflect.Type) ValueDecoder { return uint8PtrDecoder{t} },
-		reflect.Uint16:        func(t reflect.Type) ValueDecoder { return uint16PtrDecoder{t} },
-		reflect.Uint32:        func(t reflect.Type) ValueDecoder { return uint32PtrDecoder{t} },
-		reflect.Uint64:        func(t reflect.Type) ValueDecoder { return uint64PtrDecoder{t} },
-		reflect.Uintptr:       func(t reflect.Type) ValueDecoder { return uintptrPtrDecoder{t} },
-		reflect.Float32:       func(t reflect.Type) ValueDecoder { return float32PtrDecoder{t} },
-		reflect.Float64:       func(t reflect.Type) ValueDecoder { return float64PtrDecoder{t} },
-		reflect.Complex64:     func(t reflect.Type) ValueDecoder { return complex64PtrDecoder{t} },
-		reflect.Complex128:    func(t reflect.Type) ValueDecoder { return complex128PtrDecoder{t} },
-		reflect.Array:         getArrayPtrDecoder,
+		reflect.Bool:          func(c *Codec, t reflect.Type) ValueDecoder { return boolPtrDecoder{t} },
+		reflect.Int:           func(c *Codec, t reflect.Type) ValueDecoder { return intPtrDecoder{t} },
+		reflect.Int8:          func(c *Codec, t reflect.Type) ValueDecoder { return int8PtrDecoder{t} },
+		reflect.Int16:         func(c *Codec, t reflect.Type) ValueDecoder { return int16PtrDecoder{t} },
+		reflect.Int32:         func(c *Codec, t reflect.Type) ValueDecoder { return int32PtrDecoder{t} },
+		reflect.Int64:         func(c *Codec, t reflect.Type) ValueDecoder { return int64PtrDecoder{t} },
+		reflect.Uint:          func(c *Codec, t reflect.Type) ValueDecoder { return uintPtrDecoder{t} },
+		reflect.Uint8:         func(c *Codec, t reflect.Type) ValueDecoder { return uint8PtrDecoder{t} },
+		reflect.Uint16:        func(c *Codec, t reflect.Type) ValueDecoder { return uint16PtrDecoder{t} },
+		reflect.Uint32:        func(c *Codec, t reflect.Type) ValueDecoder { return uint32PtrDecoder{t} },
+		reflect.Uint64:        func(c *Codec, t reflect.Type) ValueDecoder { return uint64PtrDecoder{t} },
+		reflect.Uintptr:       func(c *Codec, t reflect.Type) ValueDecoder { return uintptrPtrDecoder{t} },
+		reflect.Float32:       func(c *Codec, t reflect.Type) ValueDecoder { return float32PtrDecoder{t} },
+		reflect.Float64:       func(c *Codec, t reflect.Type) ValueDecoder { return float64PtrDecoder{t} },
+		reflect.Complex64:     func(c *Codec, t reflect.Type) ValueDecoder { return complex64PtrDecoder{t} },
+		reflect.Complex128:    func(c *Codec, t reflect.Type) ValueDecoder { return complex128PtrDecoder{t} },
+		reflect.Array:         (*Codec).getArrayPtrDecoder,
 		reflect.Chan:          invalidDecoder,
 		reflect.Func:          invalidDecoder,
-		reflect.Interface:     func(t reflect.Type) ValueDecoder { return interfacePtrDecoder{t} },
-		reflect.Map:           getMapPtrDecoder,
-		reflect.Ptr:           getPtrPtrDecoder,
-		reflect.Slice:         getSlicePtrDecoder,
-		reflect.String:        func(t reflect.Type) ValueDecoder { return stringPtrDecoder{t} },
-		reflect.Struct:        getStructPtrDecoder,
+		reflect.Interface:     (*Codec).getInterfacePtrDecoder,
+		reflect.Map:           (*Codec).getMapPtrDecoder,
+		reflect.Ptr:           (*Codec).getPtrPtrDecoder,
+		reflect.Slice:         (*Codec).getSlicePtrDecoder,
+		reflect.String:        func(c *Codec, t reflect.Type) ValueDecoder { return stringPtrDecoder{t} },
+		reflect.Struct:        (*Codec).getStructPtrDecoder,
 		reflect.UnsafePointer: invalidDecoder,
 	}
 }
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/ptr_encoder.go                                  |
 |                                                          |
-| LastModified: Apr 12, 2020                               |
+| LastModified: Oct 18, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -86,6 +86,13 @@ func (enc *Encoder) fastWritePtr(v interface{}) (ok bool) {
 	return
 }
 func (enc *Encoder) writePtr(v interface{}, encode func(m ValueEncoder, v interface{})) {
+	c := enc.getCodec()
+	if c.isCustom() {
+		if valenc := c.ownEncoder(reflect.TypeOf(v).Elem()); valenc != nil && !reflect2.IsNil(v) {
+			encode(valenc, v)
+			return
+		}
+	}
 	if enc.fastWritePtr(v) {
 		return
 	}
@@ -99,7 +106,7 @@ func (enc *Encoder) writePtr(v interface{}, encode func(m ValueEncoder, v interf
 		}
 	}
 	et := e.Type()
-	if valenc := getOtherEncoder(et); valenc != nil {
+	if valenc := c.getOtherEncoder(et); valenc != nil {
 		encode(valenc, v)
 		return
 	}
@@ -139,7 +146,7 @@ func (enc *Encoder) writePtr(v interface{}, encode func(m ValueEncoder, v interf
 	case reflect.Array:
 		encode(arrayenc, v)
 	case reflect.Struct:
-		encode(getStructEncoder(et), v)
+		encode(c.getStructEncoder(et), v)
 	case reflect.Slice:
 		encode(slcenc, v)
 	case reflect.Map:
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/relect.go                                       |
 |                                                          |
-| LastModified: Jun 27, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -101,3 +101,6 @@ var bigFloatType = reflect.TypeOf((*big.Float)(nil))
 var bigRatType = reflect.TypeOf((*big.Rat)(nil))
 
 var listType = reflect.TypeOf((*list.List)(nil))
+var objectType = reflect.TypeOf((*Object)(nil)).Elem()
+var valueType = reflect.TypeOf((*Value)(nil)).Elem()
+var valuePtrType = reflect.TypeOf((*Value)(nil))
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/slice_decoder_test.go                           |
 |                                                          |
-| LastModified: Jun 13, 2020                               |
+| LastModified: Oct 18, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -19,6 +19,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/uuid"
+
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
 )
@@ -102,6 +104,22 @@ func TestDecodeBigIntSlice(t *testing.T) {
 	assert.EqualError(t, dec.Error, `hprose/encoding: can not cast int to []*big.Int`) // 1
 }
 
+func TestDecode2dSlice(t *testing.T) {
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	sb := new(strings.Builder)
+	enc := NewEncoder(sb)
+	enc.Encode([][]uuid.UUID{{id}, nil})
+	enc.Encode([][]int{{1, 2}, {3}})
+	dec := NewDecoder(([]byte)(sb.String()))
+	var s1 [][]uuid.UUID
+	dec.Decode(&s1)
+	assert.Equal(t, [][]uuid.UUID{{id}, nil}, s1)
+	s2 := make([][]int, 0, 4)
+	dec.Decode(&s2)
+	assert.Equal(t, [][]int{{1, 2}, {3}}, s2)
+	assert.NoError(t, dec.Error)
+}
+
 func BenchmarkDecodeIntSlice(b *testing.B) {
 	sb := new(strings.Builder)
 	enc := NewEncoder(sb)
@@ -245,3 +263,19 @@ func BenchmarkJsonDecodeBigIntSlice(b *testing.B) {
 		dec.Decode(&slice)
 	}
 }
+
+func BenchmarkDecodeLongIntSlice(b *testing.B) {
+	slice := make([]int, 100)
+	for i := range slice {
+		slice[i] = i
+	}
+	enc := NewEncoder(nil)
+	enc.Encode(slice)
+	data := enc.Bytes()
+	dec := NewDecoder(nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dec.ResetBytes(data)
+		dec.Decode(&slice)
+	}
+}
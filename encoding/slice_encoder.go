@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/slice_encoder.go                                |
 |                                                          |
-| LastModified: Apr 12, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,7 +15,10 @@ package encoding
 
 import (
 	"reflect"
+	"time"
+	"unsafe"
 
+	"github.com/google/uuid"
 	"github.com/modern-go/reflect2"
 )
 
@@ -53,352 +56,93 @@ func (enc *Encoder) writeSlice(v interface{}) {
 		enc.buf = append(enc.buf, TagList, TagOpenbrace, TagClosebrace)
 		return
 	}
+	if !enc.enterValue(reflect.TypeOf(v), *(*unsafe.Pointer)(reflect2.PtrOf(v)), count) {
+		return
+	}
 	enc.WriteListHead(count)
 	enc.writeSliceBody(v, count)
 	enc.WriteFoot()
+	enc.leaveValue()
 }
 
 func (enc *Encoder) writeSliceBody(v interface{}, n int) {
-	switch v := v.(type) {
-	case []uint16:
-		enc.writeUint16SliceBody(v, n)
-	case []uint32:
-		enc.writeUint32SliceBody(v, n)
-	case []uint64:
-		enc.writeUint64SliceBody(v, n)
-	case []uint:
-		enc.writeUintSliceBody(v, n)
-	case []int8:
-		enc.writeInt8SliceBody(v, n)
-	case []int16:
-		enc.writeInt16SliceBody(v, n)
-	case []int32:
-		enc.writeInt32SliceBody(v, n)
-	case []int64:
-		enc.writeInt64SliceBody(v, n)
-	case []int:
-		enc.writeIntSliceBody(v, n)
-	case []bool:
-		enc.writeBoolSliceBody(v, n)
-	case []float32:
-		enc.writeFloat32SliceBody(v, n)
-	case []float64:
-		enc.writeFloat64SliceBody(v, n)
-	case []complex64:
-		enc.writeComplex64SliceBody(v, n)
-	case []complex128:
-		enc.writeComplex128SliceBody(v, n)
-	case []string:
-		enc.writeStringSliceBody(v, n)
-	case []interface{}:
-		enc.writeInterfaceSliceBody(v, n)
-	case [][]uint16:
-		enc.write2dUint16SliceBody(v, n)
-	case [][]uint32:
-		enc.write2dUint32SliceBody(v, n)
-	case [][]uint64:
-		enc.write2dUint64SliceBody(v, n)
-	case [][]uint:
-		enc.write2dUintSliceBody(v, n)
-	case [][]int8:
-		enc.write2dInt8SliceBody(v, n)
-	case [][]int16:
-		enc.write2dInt16SliceBody(v, n)
-	case [][]int32:
-		enc.write2dInt32SliceBody(v, n)
-	case [][]int64:
-		enc.write2dInt64SliceBody(v, n)
-	case [][]int:
-		enc.write2dIntSliceBody(v, n)
-	case [][]bool:
-		enc.write2dBoolSliceBody(v, n)
-	case [][]float32:
-		enc.write2dFloat32SliceBody(v, n)
-	case [][]float64:
-		enc.write2dFloat64SliceBody(v, n)
-	case [][]complex64:
-		enc.write2dComplex64SliceBody(v, n)
-	case [][]complex128:
-		enc.write2dComplex128SliceBody(v, n)
-	case [][]string:
-		enc.write2dStringSliceBody(v, n)
-	case [][]interface{}:
-		enc.write2dInterfaceSliceBody(v, n)
-	case [][]byte:
-		enc.writeBytesSliceBody(v, n)
-	default:
+	if writeBody := sliceBodyWriters[reflect2.RTypeOf(v)]; writeBody != nil && !enc.getCodec().isCustom() {
+		writeBody(enc, v)
+	} else {
 		enc.writeOtherSliceBody(v, n)
 	}
 }
 
-func (enc *Encoder) writeInt8SliceBody(slice []int8, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteInt8(slice[i])
-	}
-}
-
-func (enc *Encoder) writeInt16SliceBody(slice []int16, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteInt16(slice[i])
-	}
-}
-
-func (enc *Encoder) writeInt32SliceBody(slice []int32, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteInt32(slice[i])
-	}
-}
-
-func (enc *Encoder) writeInt64SliceBody(slice []int64, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteInt64(slice[i])
-	}
-}
-
-func (enc *Encoder) writeIntSliceBody(slice []int, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteInt(slice[i])
-	}
-}
-
-func (enc *Encoder) writeUint16SliceBody(slice []uint16, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteUint16(slice[i])
-	}
-}
-
-func (enc *Encoder) writeUint32SliceBody(slice []uint32, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteUint32(slice[i])
-	}
-}
-
-func (enc *Encoder) writeUint64SliceBody(slice []uint64, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteUint64(slice[i])
-	}
-}
-
-func (enc *Encoder) writeUintSliceBody(slice []uint, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteUint(slice[i])
-	}
-}
-
-func (enc *Encoder) writeBoolSliceBody(slice []bool, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteBool(slice[i])
-	}
-}
-
-func (enc *Encoder) writeFloat32SliceBody(slice []float32, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteFloat32(slice[i])
-	}
-}
-
-func (enc *Encoder) writeFloat64SliceBody(slice []float64, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteFloat64(slice[i])
-	}
-}
-
-func (enc *Encoder) writeComplex64SliceBody(slice []complex64, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteComplex64(slice[i])
-	}
-}
-
-func (enc *Encoder) writeComplex128SliceBody(slice []complex128, n int) {
-	for i := 0; i < n; i++ {
-		enc.WriteComplex128(slice[i])
-	}
-}
-
-func (enc *Encoder) writeStringSliceBody(slice []string, n int) {
-	for i := 0; i < n; i++ {
-		enc.EncodeString(slice[i])
-	}
-}
-
-func (enc *Encoder) writeInterfaceSliceBody(slice []interface{}, n int) {
-	for i := 0; i < n; i++ {
-		enc.encode(slice[i])
-	}
-}
-
-func (enc *Encoder) write2dInt8SliceBody(slice [][]int8, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeInt8SliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dInt16SliceBody(slice [][]int16, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeInt16SliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dInt32SliceBody(slice [][]int32, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeInt32SliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dInt64SliceBody(slice [][]int64, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeInt64SliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dIntSliceBody(slice [][]int, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeIntSliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dUint16SliceBody(slice [][]uint16, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeUint16SliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dUint32SliceBody(slice [][]uint32, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeUint32SliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dUint64SliceBody(slice [][]uint64, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeUint64SliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dUintSliceBody(slice [][]uint, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeUintSliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dBoolSliceBody(slice [][]bool, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeBoolSliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dFloat32SliceBody(slice [][]float32, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeFloat32SliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dFloat64SliceBody(slice [][]float64, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeFloat64SliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
-}
-
-func (enc *Encoder) write2dComplex64SliceBody(slice [][]complex64, n int) {
-	enc.AddReferenceCount(n)
+func (enc *Encoder) writeOtherSliceBody(slice interface{}, n int) {
+	t := reflect2.TypeOf(slice).(*reflect2.UnsafeSliceType)
+	et := t.Elem()
+	ptr := reflect2.PtrOf(slice)
 	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeComplex64SliceBody(slice[i], m)
-		enc.WriteFoot()
+		enc.encode(et.UnsafeIndirect(t.UnsafeGetIndex(ptr, i)))
 	}
 }
 
-func (enc *Encoder) write2dComplex128SliceBody(slice [][]complex128, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.WriteListHead(m)
-		enc.writeComplex128SliceBody(slice[i], m)
-		enc.WriteFoot()
+func writeSliceBody[E any](enc *Encoder, slice []E, writeElem func(*Encoder, E)) {
+	for i := range slice {
+		writeElem(enc, slice[i])
+		enc.autoFlush()
 	}
 }
 
-func (enc *Encoder) write2dStringSliceBody(slice [][]string, n int) {
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.AddReferenceCount(1)
-		enc.WriteListHead(m)
-		enc.writeStringSliceBody(slice[i], m)
-		enc.WriteFoot()
-	}
+func (enc *Encoder) writeUintptr(i uintptr) {
+	enc.WriteUint64(uint64(i))
 }
 
-func (enc *Encoder) write2dInterfaceSliceBody(slice [][]interface{}, n int) {
-	for i := 0; i < n; i++ {
-		m := len(slice[i])
-		enc.AddReferenceCount(1)
-		enc.WriteListHead(m)
-		enc.writeInterfaceSliceBody(slice[i], m)
-		enc.WriteFoot()
+func (enc *Encoder) encodeBytes(bytes []byte) {
+	if bytes == nil {
+		enc.WriteNil()
+		return
 	}
+	enc.AddReferenceCount(1)
+	enc.buf = appendBytes(enc.buf, bytes)
 }
 
-func (enc *Encoder) writeBytesSliceBody(slice [][]byte, n int) {
-	enc.AddReferenceCount(n)
-	for i := 0; i < n; i++ {
-		enc.buf = appendBytes(enc.buf, slice[i])
-	}
+func (enc *Encoder) encodeTime(t time.Time) {
+	enc.AddReferenceCount(1)
+	enc.writeTime(t)
 }
 
-func (enc *Encoder) writeOtherSliceBody(slice interface{}, n int) {
-	t := reflect2.TypeOf(slice).(*reflect2.UnsafeSliceType)
-	et := t.Elem()
-	ptr := reflect2.PtrOf(slice)
-	for i := 0; i < n; i++ {
-		enc.encode(et.UnsafeIndirect(t.UnsafeGetIndex(ptr, i)))
-	}
+func (enc *Encoder) encodeUUID(id uuid.UUID) {
+	enc.AddReferenceCount(1)
+	enc.writeUUID(id)
+}
+
+// sliceBodyWriters maps the runtime type of []E to its body writer. It and
+// mapBodyWriters, sliceDecoders and mapDecoders are only written in init, so
+// they are read without locking. Only the slices of scalars and the common
+// maps are registered, since each of them is a generic instantiation.
+var sliceBodyWriters = map[uintptr]func(enc *Encoder, v interface{}){}
+
+func registerSliceBodyWriter[E any](writeElem func(*Encoder, E)) {
+	sliceBodyWriters[reflect2.RTypeOf(([]E)(nil))] = func(enc *Encoder, v interface{}) {
+		writeSliceBody(enc, v.([]E), writeElem)
+	}
+}
+
+func init() {
+	registerSliceBodyWriter((*Encoder).WriteBool)
+	registerSliceBodyWriter((*Encoder).WriteInt)
+	registerSliceBodyWriter((*Encoder).WriteInt8)
+	registerSliceBodyWriter((*Encoder).WriteInt16)
+	registerSliceBodyWriter((*Encoder).WriteInt32)
+	registerSliceBodyWriter((*Encoder).WriteInt64)
+	registerSliceBodyWriter((*Encoder).WriteUint)
+	registerSliceBodyWriter((*Encoder).WriteUint16)
+	registerSliceBodyWriter((*Encoder).WriteUint32)
+	registerSliceBodyWriter((*Encoder).WriteUint64)
+	registerSliceBodyWriter((*Encoder).writeUintptr)
+	registerSliceBodyWriter((*Encoder).WriteFloat32)
+	registerSliceBodyWriter((*Encoder).WriteFloat64)
+	registerSliceBodyWriter((*Encoder).WriteComplex64)
+	registerSliceBodyWriter((*Encoder).WriteComplex128)
+	registerSliceBodyWriter((*Encoder).EncodeString)
+	registerSliceBodyWriter((*Encoder).encode)
+	registerSliceBodyWriter((*Encoder).encodeBytes)
+	registerSliceBodyWriter((*Encoder).encodeTime)
+	registerSliceBodyWriter((*Encoder).encodeUUID)
 }
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/slice_encoder_test.go                           |
 |                                                          |
-| LastModified: Mar 20, 2020                               |
+| LastModified: Oct 18, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -18,6 +18,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -397,3 +399,39 @@ func TestEncode2dSlice(t *testing.T) {
 	enc.Reset()
 	sb.Reset()
 }
+
+func TestEncodeGenericSlice(t *testing.T) {
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	assert.NoError(t, enc.Encode([][]uuid.UUID{{id}, nil, {}}))
+	assert.NoError(t, enc.Encode([][]uintptr{{1, 2}}))
+	assert.NoError(t, enc.Encode([][]string{{"hello", "hello"}}))
+	assert.Equal(t, `a3{a1{g{00000000-0000-0000-0000-000000000001}}na{}}a1{a2{12}}a1{a2{s5"hello"r8;}}`, sb.String())
+}
+
+func benchmarkEncodeSlice(b *testing.B, slice interface{}) {
+	enc := NewEncoder(nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc.Encode(slice)
+		enc.ResetBuffer(enc.Bytes()[:0])
+	}
+}
+
+func BenchmarkEncodeIntSlice(b *testing.B) {
+	benchmarkEncodeSlice(b, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+}
+
+func BenchmarkEncodeStringSlice(b *testing.B) {
+	benchmarkEncodeSlice(b, []string{"one", "two", "three", "four", "five", "six", "seven", "eight"})
+}
+
+func BenchmarkEncode2dIntSlice(b *testing.B) {
+	benchmarkEncodeSlice(b, [][]int{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}, {13, 14, 15, 16}})
+}
+
+func BenchmarkEncode2dUUIDSlice(b *testing.B) {
+	id := uuid.New()
+	benchmarkEncodeSlice(b, [][]uuid.UUID{{id, id}, {id, id}})
+}
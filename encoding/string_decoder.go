@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/string_decoder.go                               |
 |                                                          |
-| LastModified: Jun 27, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -195,6 +195,8 @@ func (dec *Decoder) decodeString(t reflect.Type, tag byte) string {
 		return dec.ReadDateTime().String()
 	case TagGUID:
 		return dec.ReadUUID().String()
+	case TagRef:
+		return readReference[string](dec)
 	default:
 		dec.decodeError(t, tag)
 	}
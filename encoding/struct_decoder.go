@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/struct_decoder.go                               |
 |                                                          |
-| LastModified: Jun 25, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,7 +15,6 @@ package encoding
 
 import (
 	"reflect"
-	"sync"
 	"unsafe"
 
 	"github.com/modern-go/reflect2"
@@ -34,26 +33,41 @@ func (dec *Decoder) readObjectAsMap(structInfo structInfo) map[string]interface{
 	return m
 }
 
-func (dec *Decoder) readObject(structInfo structInfo) interface{} {
-	obj := structInfo.t.New()
-	dec.AddReference(obj)
-	ptr := reflect2.PtrOf(obj)
-	for _, name := range structInfo.names {
-		if field, ok := structInfo.fields[name]; ok {
-			field.Decode(dec, field.Type.Type1(), field.Field.UnsafeGet(ptr))
+func (dec *Decoder) readFields(ptr unsafe.Pointer, slots []*FieldAccessor) {
+	for _, field := range slots {
+		if field != nil {
+			field.Decode(dec, field.Type.Type1(), field.alloc(ptr))
 		} else {
 			dec.decodeInterface(interfaceType, dec.NextByte())
 		}
 	}
 	dec.Skip()
+}
+
+func (dec *Decoder) readObject(structInfo structInfo) interface{} {
+	obj := structInfo.t.New()
+	dec.AddReference(obj)
+	ptr := reflect2.PtrOf(obj)
+	structInfo.fields.callDefaults(ptr)
+	structInfo.fields.setDefaults(dec, ptr, structInfo.slots)
+	dec.readFields(ptr, structInfo.slots)
+	dec.afterDecode(obj)
 	return obj
 }
 
 // ReadObject reads object and add reference
 func (dec *Decoder) ReadObject() interface{} {
-	index := dec.ReadInt()
+	index, ok := dec.readClassIndex()
+	if !ok {
+		return nil
+	}
 	structInfo := dec.getStructInfo(index)
 	if structInfo.fields == nil {
+		if dec.ObjectType == ObjectTypeObject {
+			o := &Object{}
+			dec.readObjectAsObject(structInfo, o)
+			return o
+		}
 		return dec.readObjectAsMap(structInfo)
 	}
 	return dec.readObject(structInfo)
@@ -61,52 +75,72 @@ func (dec *Decoder) ReadObject() interface{} {
 
 // structDecoder is the implementation of ValueEncoder for named struct.
 type structDecoder struct {
-	t      *reflect2.UnsafeStructType
-	fields map[string]FieldAccessor
-	lock   sync.RWMutex
+	t     *reflect2.UnsafeStructType
+	table *fieldTable
 }
 
-func (valdec *structDecoder) decodeField(dec *Decoder, ptr unsafe.Pointer, name string) {
-	if field, ok := valdec.fields[name]; ok {
-		field.Decode(dec, field.Type.Type1(), field.Field.UnsafeGet(ptr))
+func (valdec *structDecoder) decodeField(dec *Decoder, ptr unsafe.Pointer, fields *fieldMap, name string) {
+	if field, ok := fields.get(name); ok {
+		field.Decode(dec, field.Type.Type1(), field.alloc(ptr))
+	} else if fields != nil && fields.extra != nil {
+		decodeExtra(dec, fields.extra.alloc(ptr), name)
 	} else {
 		dec.decodeInterface(interfaceType, dec.NextByte())
 	}
 }
 
 func (valdec *structDecoder) decodeObject(dec *Decoder, p interface{}) {
-	index := dec.ReadInt()
-	structInfo := dec.getStructInfo(index)
+	index, ok := dec.readClassIndex()
+	if !ok {
+		return
+	}
+	slots := dec.getFieldSlots(index, valdec.t.Type1())
 	dec.AddReference(p)
 	ptr := reflect2.PtrOf(p)
-	valdec.lock.RLock()
-	defer valdec.lock.RUnlock()
-	for _, name := range structInfo.names {
-		valdec.decodeField(dec, ptr, name)
-	}
-	dec.Skip()
+	fields, _ := valdec.table.load().(*fieldMap)
+	fields.callDefaults(ptr)
+	fields.setDefaults(dec, ptr, slots)
+	dec.readFields(ptr, slots)
 }
 
 func (valdec *structDecoder) decodeMapAsObject(dec *Decoder, p interface{}) {
 	ptr := reflect2.PtrOf(p)
-	valdec.lock.RLock()
-	defer valdec.lock.RUnlock()
+	fields, _ := valdec.table.load().(*fieldMap)
 	count := dec.ReadInt()
 	dec.AddReference(p)
+	fields.callDefaults(ptr)
+	var seen []bool
+	if fields != nil && len(fields.defaults) > 0 {
+		seen = make([]bool, len(fields.defaults))
+	}
 	for i := 0; i < count; i++ {
-		valdec.decodeField(dec, ptr, dec.decodeString(stringType, dec.NextByte()))
+		name := dec.decodeString(stringType, dec.NextByte())
+		if seen != nil {
+			fields.markDefault(seen, name)
+		}
+		valdec.decodeField(dec, ptr, fields, name)
 	}
 	dec.Skip()
+	if seen != nil {
+		fields.setAbsentDefaults(dec, ptr, seen)
+	}
 }
 
 func (valdec *structDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 	switch tag {
 	case TagObject:
 		valdec.decodeObject(dec, p)
+		dec.afterDecode(p)
 	case TagMap:
 		valdec.decodeMapAsObject(dec, p)
+		dec.afterDecode(p)
 	case TagEmpty:
 		valdec.t.UnsafeSet(reflect2.PtrOf(p), valdec.t.UnsafeNew())
+	case TagRef:
+		dec.decodeReference(p)
+	case TagClass:
+		dec.ReadStruct()
+		valdec.Decode(dec, p, dec.NextByte())
 	default:
 		dec.decodeError(valdec.Type(), tag)
 	}
@@ -117,15 +151,20 @@ func (valdec *structDecoder) Type() reflect.Type {
 }
 
 // newStructDecoder returns a ValueDecoder for struct T.
-func newStructDecoder(t reflect.Type) *structDecoder {
-	decoder := &structDecoder{t: reflect2.Type2(t).(*reflect2.UnsafeStructType)}
-	decoder.lock.Lock()
-	defer decoder.lock.Unlock()
-	RegisterValueDecoder(decoder)
-	decoder.fields = getFieldMap(t)
+func (c *Codec) newStructDecoder(t reflect.Type) *structDecoder {
+	decoder := &structDecoder{
+		t:     reflect2.Type2(t).(*reflect2.UnsafeStructType),
+		table: newFieldTable(),
+	}
+	c.storeValueDecoder(decoder)
+	decoder.table.build(func() interface{} {
+		return c.getFieldMap(t)
+	}, func() {
+		c.valueDecoders.Delete(t)
+	})
 	return decoder
 }
 
-func getStructDecoder(t reflect.Type) ValueDecoder {
-	return newStructDecoder(t)
+func (c *Codec) getStructDecoder(t reflect.Type) ValueDecoder {
+	return c.newStructDecoder(t)
 }
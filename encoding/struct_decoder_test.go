@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/struct_encoder_test.go                          |
 |                                                          |
-| LastModified: Jun 25, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -105,3 +105,264 @@ func TestDecodeMapAsObject(t *testing.T) {
 	dec.Decode(&ts)
 	assert.Equal(t, &TestStruct{1, false, &hello, 3.14, 0}, ts)
 }
+
+func TestDecodeStructSession(t *testing.T) {
+	type TestStruct struct {
+		A int
+		B string
+	}
+
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false).Session(true)
+	enc.Encode(TestStruct{1, "hello"})
+	enc.Reset()
+	enc.Encode(TestStruct{2, "world"})
+	dec := NewDecoder(([]byte)(sb.String())).Simple(false).Session(true)
+	assert.True(t, dec.IsSession())
+	var ts TestStruct
+	dec.Decode(&ts)
+	assert.Equal(t, TestStruct{1, "hello"}, ts)
+	dec.Reset()
+	dec.Decode(&ts)
+	assert.Equal(t, TestStruct{2, "world"}, ts)
+	assert.NoError(t, dec.Error)
+}
+
+func TestDecodeStructSessionByFreshDecoder(t *testing.T) {
+	type TestStruct struct {
+		A int
+		B string
+	}
+
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false).Session(true)
+	enc.Encode(TestStruct{1, "hello"})
+	enc.Reset()
+	n := sb.Len()
+	enc.Encode(TestStruct{2, "world"})
+	enc.Encode(3)
+	data := []byte(sb.String()[n:])
+	assert.Equal(t, `o0{2s5"world"}3`, string(data))
+	targets := []func() interface{}{
+		func() interface{} { return new(TestStruct) },
+		func() interface{} { return new(*TestStruct) },
+		func() interface{} { return new(interface{}) },
+		func() interface{} { return new(map[string]interface{}) },
+		func() interface{} { return new(Object) },
+		func() interface{} { return new(Value) },
+	}
+	for _, target := range targets {
+		dec := NewDecoder(data).Simple(false)
+		p := target()
+		assert.NotPanics(t, func() { dec.Decode(p) })
+		assert.EqualError(t, dec.Error, "hprose/encoding: undefined class index 0")
+		var i int
+		dec.Decode(&i)
+		assert.Equal(t, 3, i)
+	}
+}
+
+func TestDecodeEmbeddedPointerStruct(t *testing.T) {
+	src := TestEmbeddedStruct{true, &TestEmbeddedBase{1, "Tom"}, TestEmbeddedMeta{"v", 2}}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	enc.Encode(src)
+	enc.Encode(map[string]interface{}{"name": "Jerry", "meta": map[string]interface{}{"version": 3}})
+	dec := NewDecoder(([]byte)(sb.String()))
+	var ts TestEmbeddedStruct
+	dec.Decode(&ts)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, src, ts)
+	ts = TestEmbeddedStruct{}
+	dec.Decode(&ts)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestEmbeddedStruct{false, &TestEmbeddedBase{Name: "Jerry"}, TestEmbeddedMeta{Version: 3}}, ts)
+}
+
+func TestDecodeEmbeddedStruct(t *testing.T) {
+	type Inner struct {
+		B int
+		C string
+	}
+	type TestStruct struct {
+		A int
+		Inner
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	enc.Encode(TestStruct{1, Inner{2, "hello"}})
+	assert.Equal(t, `c10"TestStruct"3{s1"a"s1"b"s1"c"}o0{12s5"hello"}`, sb.String())
+	dec := NewDecoder(([]byte)(sb.String()))
+	var ts TestStruct
+	dec.Decode(&ts)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestStruct{1, Inner{2, "hello"}}, ts)
+}
+
+type BenchmarkObject struct {
+	ID       int
+	Name     string
+	Email    string
+	Age      int
+	Score    float64
+	Active   bool
+	Tags     []string
+	Comments string
+}
+
+func benchmarkObjects() []byte {
+	objects := make([]BenchmarkObject, 1000)
+	for i := range objects {
+		objects[i] = BenchmarkObject{i, "Tom", "tom@example.com", 18, 3.5, true, []string{"a", "b"}, ""}
+	}
+	enc := NewEncoder(nil)
+	enc.Encode(objects)
+	return enc.Bytes()
+}
+
+func BenchmarkDecodeStructSlice(b *testing.B) {
+	data := benchmarkObjects()
+	dec := NewDecoder(nil)
+	var objects []BenchmarkObject
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec.ResetBytes(data).Reset()
+		dec.Decode(&objects)
+	}
+	if dec.Error != nil || len(objects) != 1000 {
+		b.Fatal(dec.Error)
+	}
+}
+
+func BenchmarkDecodeRegisteredStructSlice(b *testing.B) {
+	Register((*BenchmarkObject)(nil), "BenchmarkObject")
+	data := benchmarkObjects()
+	dec := NewDecoder(nil)
+	var objects []interface{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec.ResetBytes(data).Reset()
+		dec.Decode(&objects)
+	}
+	if dec.Error != nil || len(objects) != 1000 {
+		b.Fatal(dec.Error)
+	}
+}
+
+func TestDecodeExtraMembers(t *testing.T) {
+	type UserV2 struct {
+		ID    int    `hprose:"id"`
+		Name  string `hprose:"name"`
+		Email string `hprose:"email"`
+	}
+	type User struct {
+		ID    int                    `hprose:"id"`
+		Extra map[string]interface{} `hprose:",inline"`
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	enc.Encode(UserV2{1, "Tom", "tom@example.com"})
+	enc.Encode(map[string]interface{}{"id": 2, "name": "Jerry"})
+	dec := NewDecoder(([]byte)(sb.String()))
+	var user User
+	dec.Decode(&user)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, User{1, map[string]interface{}{"name": "Tom", "email": "tom@example.com"}}, user)
+	user = User{}
+	dec.Decode(&user)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, User{2, map[string]interface{}{"name": "Jerry"}}, user)
+
+	sb.Reset()
+	enc = NewEncoder(sb)
+	enc.Encode(User{1, map[string]interface{}{"name": "Tom", "email": "tom@example.com", "id": 3}})
+	enc.Encode(User{2, nil})
+	assert.Equal(t, `c4"User"3{s2"id"s5"email"s4"name"}o0{1s15"tom@example.com"s3"Tom"}`+
+		`c4"User"1{s2"id"}o1{2}`, sb.String())
+	dec = NewDecoder(([]byte)(sb.String()))
+	var v2 UserV2
+	dec.Decode(&v2)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, UserV2{1, "Tom", "tom@example.com"}, v2)
+}
+
+func TestEncodeAnonymousStructExtraMembers(t *testing.T) {
+	v := struct {
+		A     int
+		Extra map[string]interface{} `hprose:",extra"`
+	}{1, map[string]interface{}{"b": 2}}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	enc.Encode(v)
+	assert.Equal(t, `m2{ua1ub2}`, sb.String())
+}
+
+type TestDefaultsStruct struct {
+	Name    string `hprose:"name,alias=fullName|full_name"`
+	Age     int    `hprose:"age,default=18"`
+	Active  bool   `hprose:"active,default=true"`
+	Country string
+}
+
+func (s *TestDefaultsStruct) Defaults() {
+	s.Country = "CN"
+}
+
+func TestDecodeFieldAliasesAndDefaults(t *testing.T) {
+	type OldStruct struct {
+		FullName string `hprose:"fullName"`
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	enc.Encode(OldStruct{"Tom"})
+	enc.Encode(map[string]interface{}{"full_name": "Jerry", "age": 3, "country": "US"})
+	enc.Encode(TestDefaultsStruct{"Spike", 5, false, "UK"})
+	dec := NewDecoder(([]byte)(sb.String()))
+	var v TestDefaultsStruct
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestDefaultsStruct{"Tom", 18, true, "CN"}, v)
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestDefaultsStruct{"Jerry", 3, true, "US"}, v)
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestDefaultsStruct{"Spike", 5, false, "UK"}, v)
+}
+
+func TestInvalidDefaultValue(t *testing.T) {
+	type TestStruct struct {
+		A int `hprose:"a,default=abc"`
+	}
+	const msg = `hprose/encoding: invalid default value of field A: strconv.ParseInt: parsing "abc": invalid syntax`
+	dec := NewDecoder([]byte(`m{}`))
+	var v TestStruct
+	dec.Decode(&v)
+	assert.EqualError(t, dec.Error, msg)
+	codec := Config{}.Froze()
+	assert.EqualError(t, codec.Register((*TestStruct)(nil), "TestStruct"), msg)
+	dec = codec.NewDecoder([]byte(`c10"TestStruct"0{}o0{}`))
+	dec.Decode(&v)
+	assert.EqualError(t, dec.Error, msg)
+}
+
+func TestDecodeDefaultValueCopies(t *testing.T) {
+	type TestStruct struct {
+		Count *int   `hprose:"count,default=5"`
+		Data  []byte `hprose:"data,default=abc"`
+		Name  string `hprose:"name,default=none"`
+	}
+	var a, b TestStruct
+	dec := NewDecoder([]byte(`m{}`))
+	dec.Decode(&a)
+	assert.NoError(t, dec.Error)
+	*a.Count = 99
+	a.Data[0] = 'x'
+	dec = NewDecoder([]byte(`c10"TestStruct"1{s4"name"}o0{s3"Tom"}`))
+	dec.Decode(&b)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, 5, *b.Count)
+	assert.Equal(t, []byte("abc"), b.Data)
+	assert.Equal(t, "Tom", b.Name)
+	assert.False(t, a.Count == b.Count)
+}
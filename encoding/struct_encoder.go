@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/struct_encoder.go                               |
 |                                                          |
-| LastModified: Jun 25, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -16,16 +16,106 @@ package encoding
 import (
 	"fmt"
 	"reflect"
-	"sync"
+	"sort"
+	"unsafe"
 
 	"github.com/modern-go/reflect2"
 )
 
-// structEncoder is the implementation of ValueEncoder for named struct/*struct.
-type structEncoder struct {
+// structEncoderInfo is the immutable field table of structEncoder.
+type structEncoderInfo struct {
 	fields   []FieldAccessor
+	extra    *FieldAccessor
+	aliases  map[string]bool
+	name     string
 	metadata []byte
-	lock     sync.RWMutex
+	head     int
+}
+
+func newExtraAliases(fields []FieldAccessor, extra *FieldAccessor) map[string]bool {
+	if extra == nil {
+		return nil
+	}
+	aliases := make(map[string]bool, len(fields))
+	for i := range fields {
+		aliases[fields[i].Alias] = true
+	}
+	return aliases
+}
+
+// extraMembers returns the catch-all field of the struct at p and its sorted
+// keys which are not the aliases of the fields.
+func (info *structEncoderInfo) extraMembers(p unsafe.Pointer) (extra map[string]interface{}, names []string) {
+	if info.extra == nil {
+		return
+	}
+	fp := info.extra.get(p)
+	if fp == nil {
+		return
+	}
+	extra = *(*map[string]interface{})(fp)
+	for name := range extra {
+		if !info.aliases[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// extraSignature returns the class signature of the struct named name with
+// the extra members, it is the same as the signature of Object.
+func (info *structEncoderInfo) extraSignature(name string, names []string) string {
+	n := len(name) + 21
+	for i := range info.fields {
+		n += len(info.fields[i].Alias) + 21
+	}
+	for _, s := range names {
+		n += len(s) + 21
+	}
+	buf := make([]byte, 0, n)
+	buf = appendSignature(buf, name)
+	for i := range info.fields {
+		buf = appendSignature(buf, info.fields[i].Alias)
+	}
+	for _, s := range names {
+		buf = appendSignature(buf, s)
+	}
+	return unsafeString(buf)
+}
+
+func (enc *Encoder) writeExtraStructType(info *structEncoderInfo, name string, names []string) int {
+	return enc.writeStructType(info.extraSignature(name, names), func() {
+		n := len(info.fields) + len(names)
+		enc.AddReferenceCount(n)
+		enc.buf = append(enc.buf, TagClass)
+		enc.buf = appendName(enc.buf, name, "struct name")
+		enc.buf = AppendUint64(enc.buf, uint64(n))
+		enc.buf = append(enc.buf, TagOpenbrace)
+		for i := range info.fields {
+			enc.buf = append(enc.buf, TagString)
+			enc.buf = appendName(enc.buf, info.fields[i].Alias, "struct field name or alias")
+		}
+		for _, s := range names {
+			enc.buf = append(enc.buf, TagString)
+			enc.buf = appendName(enc.buf, s, "extra member name")
+		}
+		enc.buf = append(enc.buf, TagClosebrace)
+	})
+}
+
+func (enc *Encoder) writeExtraMembers(extra map[string]interface{}, names []string) {
+	for _, name := range names {
+		enc.encode(extra[name])
+	}
+}
+
+// structEncoder is the implementation of ValueEncoder for named struct/*struct.
+type structEncoder struct {
+	t            reflect.Type
+	likePtr      bool
+	beforeEncode bool
+	table        *fieldTable
 }
 
 func (valenc *structEncoder) Encode(enc *Encoder, v interface{}) {
@@ -33,27 +123,66 @@ func (valenc *structEncoder) Encode(enc *Encoder, v interface{}) {
 }
 
 func (valenc *structEncoder) Write(enc *Encoder, v interface{}) {
-	valenc.lock.RLock()
-	defer valenc.lock.RUnlock()
-	fields := valenc.fields
+	table := valenc.table.load()
+	if table == nil {
+		enc.Error = UnsupportedTypeError{valenc.t}
+		enc.WriteNil()
+		return
+	}
+	info := table.(*structEncoderInfo)
+	fields := info.fields
 	n := len(fields)
 	t := reflect.TypeOf(v)
 	st := t
 	if t.Kind() == reflect.Ptr {
 		st = t.Elem()
-	} else if n == 1 {
+	} else if valenc.likePtr || valenc.beforeEncode {
 		v = toPtr(t, v)
 	}
-	var r = enc.WriteStructType(st, func() {
-		enc.AddReferenceCount(n)
-		enc.buf = append(enc.buf, valenc.metadata...)
-	})
-	enc.SetReference(v)
 	p := reflect2.PtrOf(v)
+	if valenc.beforeEncode && !enc.beforeEncode(st, p) {
+		enc.WriteNil()
+		return
+	}
+	extra, names := info.extraMembers(p)
+	var r int
+	if len(names) > 0 {
+		name, err := enc.getRegistry().GetName(st)
+		if err != nil {
+			if enc.Error == nil {
+				enc.Error = err
+			}
+			enc.WriteNil()
+			return
+		}
+		r = enc.writeExtraStructType(info, name, names)
+	} else {
+		r = enc.WriteStructType(st, func() {
+			enc.AddReferenceCount(n)
+			name, err := enc.getRegistry().GetName(st)
+			if err != nil && enc.Error == nil {
+				enc.Error = err
+			}
+			if err == nil && name != info.name {
+				enc.buf = append(enc.buf, TagClass)
+				enc.buf = appendName(enc.buf, name, "struct name")
+				enc.buf = append(enc.buf, info.metadata[info.head:]...)
+			} else {
+				enc.buf = append(enc.buf, info.metadata...)
+			}
+		})
+	}
+	enc.SetReference(v)
 	enc.WriteObjectHead(r)
 	for i := 0; i < n; i++ {
-		fields[i].Encode(enc, fields[i].Type.UnsafeIndirect(fields[i].Field.UnsafeGet(p)))
+		if fp := fields[i].get(p); fp != nil {
+			fields[i].Encode(enc, fields[i].Type.UnsafeIndirect(fp))
+		} else {
+			enc.WriteNil()
+		}
+		enc.autoFlush()
 	}
+	enc.writeExtraMembers(extra, names)
 	enc.WriteFoot()
 }
 
@@ -71,16 +200,29 @@ func toPtr(t reflect.Type, v interface{}) interface{} {
 	return pv.Interface()
 }
 
-func newStructEncoder(t reflect.Type, name string, tag ...string) *structEncoder {
-	encoder := &structEncoder{}
-	encoder.lock.Lock()
-	defer encoder.lock.Unlock()
-	registerValueEncoder(t, encoder)
-	fields := getFields(t, tag...)
+func (c *Codec) newStructEncoder(t reflect.Type, name string, tag ...string) *structEncoder {
+	encoder := &structEncoder{
+		t:            t,
+		likePtr:      reflect2.Type2(t).LikePtr(),
+		beforeEncode: reflect.PtrTo(t).Implements(beforeEncoderType),
+		table:        newFieldTable(),
+	}
+	c.structEncoders.Store(t, encoder)
+	encoder.table.build(func() interface{} {
+		return c.newStructEncoderInfo(t, name, tag...)
+	}, func() {
+		c.structEncoders.Delete(t)
+	})
+	return encoder
+}
+
+func (c *Codec) newStructEncoderInfo(t reflect.Type, name string, tag ...string) *structEncoderInfo {
+	fields, extra := c.getFields(t, tag...)
 	n := len(fields)
 	var metadata []byte
 	metadata = append(metadata, TagClass)
 	metadata = appendName(metadata, name, "struct name")
+	head := len(metadata)
 	if n > 0 {
 		metadata = AppendUint64(metadata, uint64(n))
 	}
@@ -90,23 +232,36 @@ func newStructEncoder(t reflect.Type, name string, tag ...string) *structEncoder
 		metadata = appendName(metadata, fields[i].Alias, "struct field name or alias")
 	}
 	metadata = append(metadata, TagClosebrace)
-	encoder.fields = fields
-	encoder.metadata = metadata
-	return encoder
+	return &structEncoderInfo{
+		fields:   fields,
+		extra:    extra,
+		aliases:  newExtraAliases(fields, extra),
+		name:     name,
+		metadata: metadata,
+		head:     head,
+	}
 }
 
 // anonymousStructEncoder is the implementation of ValueEncoder for anonymous struct/*struct.
 type anonymousStructEncoder struct {
-	fields []FieldAccessor
-	lock   sync.RWMutex
+	t       reflect.Type
+	likePtr bool
+	table   *fieldTable
 }
 
-func newAnonymousStructEncoder(t reflect.Type, tag ...string) *anonymousStructEncoder {
-	encoder := &anonymousStructEncoder{}
-	encoder.lock.Lock()
-	defer encoder.lock.Unlock()
-	registerValueEncoder(t, encoder)
-	encoder.fields = getFields(t, tag...)
+func (c *Codec) newAnonymousStructEncoder(t reflect.Type, tag ...string) *anonymousStructEncoder {
+	encoder := &anonymousStructEncoder{t: t, likePtr: reflect2.Type2(t).LikePtr(), table: newFieldTable()}
+	c.structEncoders.Store(t, encoder)
+	encoder.table.build(func() interface{} {
+		fields, extra := c.getFields(t, tag...)
+		return &structEncoderInfo{
+			fields:  fields,
+			extra:   extra,
+			aliases: newExtraAliases(fields, extra),
+		}
+	}, func() {
+		c.structEncoders.Delete(t)
+	})
 	return encoder
 }
 
@@ -115,25 +270,38 @@ func (valenc *anonymousStructEncoder) Encode(enc *Encoder, v interface{}) {
 }
 
 func (valenc *anonymousStructEncoder) Write(enc *Encoder, v interface{}) {
-	valenc.lock.RLock()
-	defer valenc.lock.RUnlock()
+	table := valenc.table.load()
+	if table == nil {
+		enc.Error = UnsupportedTypeError{valenc.t}
+		enc.WriteNil()
+		return
+	}
 	enc.SetReference(v)
-	fields := valenc.fields
+	info := table.(*structEncoderInfo)
+	fields := info.fields
+	if t := reflect.TypeOf(v); t.Kind() == reflect.Struct && valenc.likePtr {
+		v = toPtr(t, v)
+	}
+	p := reflect2.PtrOf(v)
+	extra, names := info.extraMembers(p)
 	n := len(fields)
-	switch n {
-	case 0:
+	if n+len(names) == 0 {
 		enc.buf = append(enc.buf, TagMap, TagOpenbrace, TagClosebrace)
 		return
-	case 1:
-		if t := reflect.TypeOf(v); t.Kind() == reflect.Struct {
-			v = toPtr(t, v)
-		}
 	}
-	p := reflect2.PtrOf(v)
-	enc.WriteMapHead(n)
+	enc.WriteMapHead(n + len(names))
 	for i := 0; i < n; i++ {
 		enc.EncodeString(fields[i].Alias)
-		fields[i].Encode(enc, fields[i].Type.UnsafeIndirect(fields[i].Field.UnsafeGet(p)))
+		if fp := fields[i].get(p); fp != nil {
+			fields[i].Encode(enc, fields[i].Type.UnsafeIndirect(fp))
+		} else {
+			enc.WriteNil()
+		}
+		enc.autoFlush()
+	}
+	for _, name := range names {
+		enc.EncodeString(name)
+		enc.encode(extra[name])
 	}
 	enc.WriteFoot()
 }
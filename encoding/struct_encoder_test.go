@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/struct_encoder_test.go                          |
 |                                                          |
-| LastModified: Jun 25, 2020                               |
+| LastModified: Oct 18, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -18,6 +18,7 @@ import (
 	"math/big"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 	"unsafe"
@@ -298,15 +299,15 @@ func TestEncodeErrorStructField(t *testing.T) {
 }
 
 func TestAmbiguousFields(t *testing.T) {
-	assert.PanicsWithValue(t, "hprose/encoding: ambiguous fields with the same name or alias: a", func() {
-		type TestStruct struct {
-			A int `hprose:"a"`
-			B int `json:"a"`
-		}
-		sb := &strings.Builder{}
-		enc := NewEncoder(sb).Simple(false)
-		enc.Encode(TestStruct{})
-	})
+	type TestStruct struct {
+		A int `hprose:"a"`
+		B int `json:"a"`
+		C int
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	enc.Encode(TestStruct{1, 2, 3})
+	assert.Equal(t, `c10"TestStruct"1{s1"c"}o0{3}`, sb.String())
 }
 
 func TestInvalidStructName(t *testing.T) {
@@ -315,7 +316,7 @@ func TestInvalidStructName(t *testing.T) {
 			A int
 			B int
 		}
-		newStructEncoder(reflect.TypeOf((*TestStruct)(nil)).Elem(), "\xFE")
+		DefaultCodec.newStructEncoder(reflect.TypeOf((*TestStruct)(nil)).Elem(), "\xFE")
 		sb := &strings.Builder{}
 		enc := NewEncoder(sb).Simple(false)
 		enc.Encode(TestStruct{})
@@ -363,3 +364,130 @@ func TestEncodeOnePtrFieldStruct(t *testing.T) {
 	assert.Equal(t, `m1{ubc15"TestEmbedStruct"1{s1"a"}o0{1}}m1{ubr2;}m1{ubr2;}r4;`, sb.String())
 
 }
+
+func TestEncodeSelfReferencingStructConcurrently(t *testing.T) {
+	type TestNode struct {
+		Value int
+		Next  *TestNode
+	}
+	node := &TestNode{1, &TestNode{2, nil}}
+	var wg sync.WaitGroup
+	results := make([]string, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sb := &strings.Builder{}
+			enc := NewEncoder(sb)
+			enc.Encode(node)
+			results[i] = sb.String()
+		}(i)
+	}
+	wg.Wait()
+	for _, result := range results {
+		assert.Equal(t, `c8"TestNode"2{s5"value"s4"next"}o0{1o0{2n}}`, result)
+	}
+}
+
+func TestInvalidStructNamePanicAgain(t *testing.T) {
+	type TestStruct struct {
+		A int
+	}
+	for i := 0; i < 2; i++ {
+		assert.PanicsWithValue(t, "hprose/encoding: invalid UTF-8 in struct name", func() {
+			DefaultCodec.newStructEncoder(reflect.TypeOf((*TestStruct)(nil)).Elem(), "\xFE")
+		})
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	enc.Encode(TestStruct{1})
+	assert.Equal(t, `c10"TestStruct"1{s1"a"}o0{1}`, sb.String())
+}
+
+type TestEmbeddedBase struct {
+	ID   int
+	Name string
+}
+
+type TestEmbeddedMeta struct {
+	Name    string
+	Version int
+}
+
+type TestEmbeddedStruct struct {
+	Flag bool
+	*TestEmbeddedBase
+	TestEmbeddedMeta `hprose:"meta"`
+}
+
+func TestEncodeEmbeddedPointerStruct(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	enc.Encode(TestEmbeddedStruct{true, &TestEmbeddedBase{1, "Tom"}, TestEmbeddedMeta{"v", 2}})
+	enc.Encode(TestEmbeddedStruct{Flag: true})
+	assert.Equal(t, `c18"TestEmbeddedStruct"4{s4"flag"s2"iD"s4"name"s4"meta"}`+
+		`o0{t1s3"Tom"c16"TestEmbeddedMeta"2{s4"name"s7"version"}o1{uv2}}`+
+		`o0{tnno1{e0}}`, sb.String())
+}
+
+func TestEncodeEmbeddedPointerOnlyStruct(t *testing.T) {
+	type TestStruct struct {
+		*TestEmbeddedBase
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	enc.Encode(TestStruct{&TestEmbeddedBase{1, "Tom"}})
+	assert.Equal(t, `c10"TestStruct"2{s2"iD"s4"name"}o0{1s3"Tom"}`, sb.String())
+}
+
+func TestDominantFields(t *testing.T) {
+	type Inner struct {
+		A int
+		B int
+		C int `hprose:"c"`
+	}
+	type Other struct {
+		B int
+		C int
+		D int
+	}
+	type TestStruct struct {
+		Inner
+		*Other
+		A string
+	}
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	enc.Encode(TestStruct{Inner{1, 2, 3}, &Other{4, 5, 6}, "a"})
+	assert.Equal(t, `c10"TestStruct"3{s1"c"s1"d"s1"a"}o0{36ua}`, sb.String())
+}
+
+type TestRecursiveEmbeddedStruct struct {
+	*TestRecursiveEmbeddedStruct
+	A int
+}
+
+func TestEncodeRecursiveEmbeddedStruct(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	enc.Encode(TestRecursiveEmbeddedStruct{A: 1})
+	assert.Equal(t, `c27"TestRecursiveEmbeddedStruct"1{s1"a"}o0{1}`, sb.String())
+}
+
+func BenchmarkEncodeStructParallel(b *testing.B) {
+	type TestStruct struct {
+		A int
+		B string
+		C []int
+	}
+	v := &TestStruct{1, "hello", []int{1, 2, 3}}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		enc := NewEncoder(nil)
+		for pb.Next() {
+			enc.Encode(v)
+			enc.Reset()
+			enc.ResetBuffer(nil)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/struct_hooks.go                                 |
+|                                                          |
+| LastModified: Oct 18, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// BeforeEncoder is implemented by the struct types which normalize or
+// validate themselves before they are encoded. If BeforeHproseEncode returns
+// an error, the struct is encoded as nil and the error is set to
+// Encoder.Error.
+type BeforeEncoder interface {
+	BeforeHproseEncode() error
+}
+
+// AfterDecoder is implemented by the struct types which normalize or
+// validate themselves after they are decoded. If AfterHproseDecode returns
+// an error, it is set to Decoder.Error.
+type AfterDecoder interface {
+	AfterHproseDecode() error
+}
+
+var (
+	beforeEncoderType = reflect.TypeOf((*BeforeEncoder)(nil)).Elem()
+	afterDecoderType  = reflect.TypeOf((*AfterDecoder)(nil)).Elem()
+)
+
+// beforeEncode calls BeforeHproseEncode on the struct of type t at p, it
+// returns false if the hook fails.
+func (enc *Encoder) beforeEncode(t reflect.Type, p unsafe.Pointer) bool {
+	if err := reflect.NewAt(t, p).Interface().(BeforeEncoder).BeforeHproseEncode(); err != nil {
+		if enc.Error == nil {
+			enc.Error = err
+		}
+		return false
+	}
+	return true
+}
+
+// afterDecode calls AfterHproseDecode on p if it implements AfterDecoder and
+// no error has occurred while decoding it.
+func (dec *Decoder) afterDecode(p interface{}) {
+	if dec.Error != nil {
+		return
+	}
+	if v, ok := p.(AfterDecoder); ok {
+		dec.Error = v.AfterHproseDecode()
+	}
+}
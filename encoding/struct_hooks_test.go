@@ -0,0 +1,80 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/struct_hooks_test.go                            |
+|                                                          |
+| LastModified: Oct 18, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestHooksStruct struct {
+	Name string
+	Age  int
+}
+
+func (s *TestHooksStruct) BeforeHproseEncode() error {
+	if s.Age < 0 {
+		return errors.New("negative age")
+	}
+	s.Name = strings.TrimSpace(s.Name)
+	return nil
+}
+
+func (s *TestHooksStruct) AfterHproseDecode() error {
+	if s.Name == "" {
+		return errors.New("empty name")
+	}
+	s.Name = strings.ToUpper(s.Name)
+	return nil
+}
+
+func TestBeforeHproseEncode(t *testing.T) {
+	enc := NewEncoder(nil)
+	v := TestHooksStruct{" tom ", 1}
+	assert.NoError(t, enc.Encode(v))
+	assert.Equal(t, " tom ", v.Name)
+	assert.NoError(t, enc.Encode(&v))
+	assert.Equal(t, "tom", v.Name)
+	assert.Equal(t, `c15"TestHooksStruct"2{s4"name"s3"age"}o0{s3"tom"1}o0{s3"tom"1}`, enc.String())
+	enc = NewEncoder(nil)
+	assert.EqualError(t, enc.Encode(TestHooksStruct{"Tom", -1}), "negative age")
+	assert.Equal(t, `n`, enc.String())
+}
+
+func TestAfterHproseDecode(t *testing.T) {
+	enc := NewEncoder(nil)
+	enc.Encode(TestHooksStruct{"tom", 1})
+	enc.Encode(map[string]interface{}{"name": "jerry"})
+	dec := NewDecoder(enc.Bytes())
+	var v TestHooksStruct
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestHooksStruct{"TOM", 1}, v)
+	v = TestHooksStruct{}
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, TestHooksStruct{"JERRY", 0}, v)
+
+	Register((*TestHooksStruct)(nil), "TestHooksStruct")
+	dec = NewDecoder([]byte(`c15"TestHooksStruct"2{s4"name"s3"age"}o0{s3"tom"1}o0{e2}`))
+	var o interface{}
+	dec.Decode(&o)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, &TestHooksStruct{"TOM", 1}, o)
+	dec.Decode(&o)
+	assert.EqualError(t, dec.Error, "empty name")
+}
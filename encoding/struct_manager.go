@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/struct_manager.go                               |
 |                                                          |
-| LastModified: Jun 25, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -17,7 +17,8 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
-	"sync"
+	"sync/atomic"
+	"unsafe"
 
 	"github.com/modern-go/reflect2"
 )
@@ -26,11 +27,56 @@ var defaultTags = []string{"hprose", "json"}
 
 // FieldAccessor _
 type FieldAccessor struct {
-	Type   reflect2.Type
-	Alias  string
+	Type  reflect2.Type
+	Alias string
+	// Field is the struct field declared by the struct which holds it. For
+	// a field promoted through an embedded pointer, the offset of Field is
+	// relative to the embedded struct, so Field.UnsafeGet does not give its
+	// address from the outer struct.
 	Field  reflect2.StructField
 	Encode EncodeHandler
 	Decode DecodeHandler
+	embeds []embeddedPointer
+	offset uintptr
+	names  []string
+	// def is the hprose encoding of the default value given by the default
+	// tag option, and value is the default value decoded from it once when
+	// the field map is built.
+	def   []byte
+	value reflect.Value
+	// err is the error of the invalid field codec.
+	err error
+}
+
+// embeddedPointer is an embedded pointer to struct on the path to a promoted
+// field, offset is relative to the struct which embeds it.
+type embeddedPointer struct {
+	offset uintptr
+	elem   reflect2.Type
+}
+
+// get returns the pointer to the field of the struct at ptr, or nil if the
+// field is promoted through a nil embedded pointer.
+func (f *FieldAccessor) get(ptr unsafe.Pointer) unsafe.Pointer {
+	for _, e := range f.embeds {
+		if ptr = *(*unsafe.Pointer)(unsafe.Add(ptr, e.offset)); ptr == nil {
+			return nil
+		}
+	}
+	return unsafe.Add(ptr, f.offset)
+}
+
+// alloc returns the pointer to the field of the struct at ptr, the nil
+// embedded pointers on its path are allocated.
+func (f *FieldAccessor) alloc(ptr unsafe.Pointer) unsafe.Pointer {
+	for _, e := range f.embeds {
+		p := (*unsafe.Pointer)(unsafe.Add(ptr, e.offset))
+		if *p == nil {
+			*p = e.elem.UnsafeNew()
+		}
+		ptr = *p
+	}
+	return unsafe.Add(ptr, f.offset)
 }
 
 func stripOptions(tag string) string {
@@ -45,7 +91,7 @@ func _fieldAlias(tag reflect.StructTag, tagname string) string {
 	return strings.Trim(stripOptions(tag.Get(tagname)), " ")
 }
 
-func fieldAlias(tag reflect.StructTag, name string, tags []string) string {
+func tagAlias(tag reflect.StructTag, tags []string) string {
 	for _, tagname := range defaultTags {
 		if tagname != "" {
 			if alias := _fieldAlias(tag, tagname); alias != "" {
@@ -60,25 +106,206 @@ func fieldAlias(tag reflect.StructTag, name string, tags []string) string {
 			}
 		}
 	}
-	if name[0] >= 'A' && name[0] <= 'Z' {
-		name = string(name[0]-'A'+'a') + name[1:]
+	return ""
+}
+
+func fieldAlias(tag reflect.StructTag, name string, tags []string, naming FieldNaming) string {
+	if alias := tagAlias(tag, tags); alias != "" {
+		return alias
+	}
+	if naming == nil {
+		return LowerFirst(name)
+	}
+	return naming(name)
+}
+
+// fieldCandidate is a field which may be hidden by the other fields with the
+// same alias.
+type fieldCandidate struct {
+	FieldAccessor
+	depth  int
+	tagged bool
+	extra  bool
+}
+
+var extraFieldType = reflect.TypeOf((map[string]interface{})(nil))
+
+// tagOptions returns the options of the hprose tag and the custom tags.
+func tagOptions(tag reflect.StructTag, tags []string) (options []string) {
+	for _, tagname := range append([]string{"hprose"}, tags...) {
+		if tagname == "" {
+			continue
+		}
+		if opts := strings.Split(tag.Get(tagname), ","); len(opts) > 1 {
+			for _, option := range opts[1:] {
+				options = append(options, strings.TrimSpace(option))
+			}
+		}
+	}
+	return
+}
+
+// tagOption returns the value of the option key=value in options.
+func tagOption(options []string, key string) (string, bool) {
+	for _, option := range options {
+		if k, v, ok := strings.Cut(option, "="); ok && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v), true
+		}
+	}
+	return "", false
+}
+
+// isExtraField returns true if f is the catch-all field of the unknown
+// members, which is a map[string]interface{} field tagged with
+// `hprose:",inline"` or `hprose:",extra"`.
+func isExtraField(f reflect2.StructField, options []string) bool {
+	if f.Type().Type1() != extraFieldType {
+		return false
+	}
+	for _, option := range options {
+		if option == "inline" || option == "extra" {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldNames returns the additional names accepted on decoding, which are
+// given by `hprose:"name,alias=fullName|full_name"`.
+func fieldNames(options []string) (names []string) {
+	if value, ok := tagOption(options, "alias"); ok {
+		for _, name := range strings.Split(value, "|") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return
+}
+
+// defaultValue returns the hprose encoding of the default value of the
+// field which is given by `hprose:"name,default=value"` as a string, or nil
+// if the field has no default value.
+func defaultValue(options []string) []byte {
+	value, ok := tagOption(options, "default")
+	if !ok {
+		return nil
+	}
+	enc := NewEncoder(nil)
+	enc.WriteString(value)
+	return enc.Bytes()
+}
+
+// decodeDefault decodes the default value of field f with the codec.
+func (c *Codec) decodeDefault(f *FieldAccessor) (reflect.Value, error) {
+	p := reflect.New(f.Type.Type1())
+	dec := c.NewDecoder(f.def)
+	dec.Decode(p.Interface())
+	if dec.Error != nil {
+		return reflect.Value{}, DecodeError(fmt.Sprintf("hprose/encoding: invalid default value of field %s: %v", f.Field.Name(), dec.Error))
+	}
+	return p.Elem(), nil
+}
+
+// cloneValue returns a copy of v which shares no pointers, slices or maps
+// with it.
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			p := reflect.New(v.Type().Elem())
+			p.Elem().Set(cloneValue(v.Elem()))
+			return p
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			reflect.Copy(s, v)
+			for i := 0; i < v.Len(); i++ {
+				s.Index(i).Set(cloneValue(v.Index(i)))
+			}
+			return s
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			m := reflect.MakeMapWithSize(v.Type(), v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				m.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+			}
+			return m
+		}
+	}
+	return v
+}
+
+// setDefault sets a copy of the default value of the field to the struct at
+// ptr, so that each struct gets its own copy of the pointers, slices and maps.
+func (f *FieldAccessor) setDefault(ptr unsafe.Pointer) {
+	reflect.NewAt(f.Type.Type1(), f.alloc(ptr)).Elem().Set(cloneValue(f.value))
+}
+
+// decodeExtra decodes the next value into the extra field at p with key name.
+func decodeExtra(dec *Decoder, p unsafe.Pointer, name string) {
+	m := (*map[string]interface{})(p)
+	if *m == nil {
+		*m = make(map[string]interface{})
+	}
+	(*m)[name] = dec.decodeInterface(interfaceType, dec.NextByte())
+}
+
+// extraSlot returns the field slot which decodes the member named name into
+// the extra field.
+func extraSlot(extra *FieldAccessor, name string) *FieldAccessor {
+	slot := *extra
+	slot.Decode = func(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+		decodeExtra(dec, p, name)
 	}
-	return name
+	return &slot
 }
 
-func _getFields(t reflect2.StructType, tags []string, mapping map[string]bool, fields []FieldAccessor) []FieldAccessor {
+// embeddedStruct returns the struct type of an embedded struct or pointer to
+// struct field.
+func embeddedStruct(t reflect2.Type) (st reflect2.StructType, ptr bool) {
+	switch t.Kind() {
+	case reflect.Struct:
+		return t.(reflect2.StructType), false
+	case reflect.Ptr:
+		if et := t.(reflect2.PtrType).Elem(); et.Kind() == reflect.Struct {
+			return et.(reflect2.StructType), true
+		}
+	}
+	return nil, false
+}
+
+func (c *Codec) _getFields(t reflect2.StructType, tags []string, naming FieldNaming, depth int, embeds []embeddedPointer, offset uintptr, visiting map[reflect.Type]bool, fields []fieldCandidate) []fieldCandidate {
+	visiting[t.Type1()] = true
+	defer delete(visiting, t.Type1())
 	n := t.NumField()
 	for i := 0; i < n; i++ {
 		f := t.Field(i)
 		ft := f.Type()
-		kind := ft.Kind()
 
-		switch kind {
+		switch ft.Kind() {
 		case reflect.Func, reflect.Chan, reflect.UnsafePointer:
 			continue
-		case reflect.Struct:
-			if f.Anonymous() {
-				fields = _getFields(ft.(reflect2.StructType), tags, mapping, fields)
+		}
+
+		alias := tagAlias(f.Tag(), tags)
+		if alias == "-" {
+			continue
+		}
+		if f.Anonymous() && alias == "" {
+			if st, ptr := embeddedStruct(ft); st != nil {
+				if visiting[st.Type1()] {
+					continue
+				}
+				if ptr {
+					e := append(embeds[:len(embeds):len(embeds)], embeddedPointer{offset + f.Offset(), st})
+					fields = c._getFields(st, tags, naming, depth+1, e, 0, visiting, fields)
+				} else {
+					fields = c._getFields(st, tags, naming, depth+1, embeds, offset+f.Offset(), visiting, fields)
+				}
 				continue
 			}
 		}
@@ -87,64 +314,311 @@ func _getFields(t reflect2.StructType, tags []string, mapping map[string]bool, f
 			continue
 		}
 
-		name := fieldAlias(f.Tag(), f.Name(), tags)
-		if name == "-" {
+		var field fieldCandidate
+		field.Type = ft
+		field.Field = f
+		field.embeds = embeds
+		field.offset = offset + f.Offset()
+		field.depth = depth
+		options := tagOptions(f.Tag(), tags)
+		if isExtraField(f, options) {
+			field.extra = true
+			fields = append(fields, field)
 			continue
 		}
-		if mapping[name] {
-			panic(fmt.Sprintf("hprose/encoding: ambiguous fields with the same name or alias: %s", name))
+		field.names = fieldNames(options)
+		field.Alias = fieldAlias(f.Tag(), f.Name(), tags, naming)
+		field.tagged = alias != ""
+		typ := ft.Type1()
+		if name, ok := tagOption(options, "codec"); ok {
+			codec, err := c.getFieldCodec(name, f)
+			if err != nil {
+				field.err, codec = err, invalidFieldCodec(err)
+			}
+			field.Encode, field.Decode = codec.encode, codec.decode
+		}
+		if field.Encode == nil {
+			if field.Encode = c.GetEncodeHandler(typ); field.Encode == nil {
+				continue
+			}
+		}
+		if field.Decode == nil {
+			if field.Decode = c.GetDecodeHandler(typ); field.Decode == nil {
+				continue
+			}
 		}
+		field.def = defaultValue(options)
+		fields = append(fields, field)
+	}
+	return fields
+}
 
-		var field FieldAccessor
-		field.Type = ft
-		field.Alias = name
-		field.Field = f
-		typ := ft.Type1()
-		if field.Encode = GetEncodeHandler(typ); field.Encode == nil {
+// dominantField returns the index of the field which hides the others in
+// the group of fields with the same alias, or -1 if none of them does. Like
+// encoding/json, the shallowest field wins, and then the tagged one wins.
+func dominantField(candidates []fieldCandidate, group []int) int {
+	depth := candidates[group[0]].depth
+	for _, i := range group[1:] {
+		if candidates[i].depth < depth {
+			depth = candidates[i].depth
+		}
+	}
+	dominant, tagged, count := -1, -1, 0
+	for _, i := range group {
+		if candidates[i].depth != depth {
 			continue
 		}
-		if field.Decode = GetDecodeHandler(typ); field.Decode == nil {
+		count++
+		if dominant < 0 {
+			dominant = i
+		}
+		if candidates[i].tagged {
+			if tagged >= 0 {
+				return -1
+			}
+			tagged = i
+		}
+	}
+	switch {
+	case tagged >= 0:
+		return tagged
+	case count == 1:
+		return dominant
+	}
+	return -1
+}
+
+// getFields returns the fields of struct t, and the catch-all field of the
+// unknown members if any, the shallowest one wins. The tags of the codec are
+// looked up after tag.
+func (c *Codec) getFields(t reflect.Type, tag ...string) (fields []FieldAccessor, extra *FieldAccessor) {
+	naming := c.getFieldOptions(t).naming
+	tags := append(tag[:len(tag):len(tag)], c.config.Tags...)
+	candidates := c._getFields(reflect2.Type2(t).(reflect2.StructType), tags, naming, 0, nil, 0, map[reflect.Type]bool{}, nil)
+	groups := make(map[string][]int, len(candidates))
+	depth := 0
+	for i := range candidates {
+		if candidates[i].extra {
+			if extra == nil || candidates[i].depth < depth {
+				extra, depth = &candidates[i].FieldAccessor, candidates[i].depth
+			}
 			continue
 		}
+		groups[candidates[i].Alias] = append(groups[candidates[i].Alias], i)
+	}
+	fields = make([]FieldAccessor, 0, len(candidates))
+	for i := range candidates {
+		if !candidates[i].extra && dominantField(candidates, groups[candidates[i].Alias]) == i {
+			fields = append(fields, candidates[i].FieldAccessor)
+		}
+	}
+	return
+}
 
-		mapping[name] = true
-		fields = append(fields, field)
+// fieldOptions are the options of the fields of a struct type, they are set
+// when the type is registered.
+type fieldOptions struct {
+	naming          FieldNaming
+	caseInsensitive bool
+}
+
+func (c *Codec) getFieldOptions(t reflect.Type) fieldOptions {
+	if options, ok := c.fieldOptions.Load(t); ok {
+		return options.(fieldOptions)
+	}
+	return fieldOptions{c.config.FieldNaming, c.config.CaseInsensitive}
+}
+
+func (c *Codec) setFieldOptions(t reflect.Type, options fieldOptions) {
+	c.fieldOptions.Store(t, options)
+	c.fieldMaps.Delete(t)
+}
+
+// fieldMap maps the names of the fields of a struct type to their accessors.
+// If the fields are matched case-insensitively, folded maps the lowercased
+// names to the accessors. extra is the catch-all field of the unknown members.
+type fieldMap struct {
+	fields    map[string]FieldAccessor
+	folded    map[string]FieldAccessor
+	extra     *FieldAccessor
+	defaults  []FieldAccessor
+	defaulter reflect.Type
+	// err is the error of the invalid field codecs or default values, it is
+	// returned by Register and set to the Decoders of the struct.
+	err error
+}
+
+// Defaulter is implemented by the struct types which set the default values
+// of their fields. Defaults is called on the struct before its fields are
+// decoded, so the fields absent from the data keep the default values. The
+// default values given by the default tag options are set to the fields
+// absent from the data after Defaults is called.
+type Defaulter interface {
+	Defaults()
+}
+
+var defaulterType = reflect.TypeOf((*Defaulter)(nil)).Elem()
+
+// callDefaults calls the Defaults method of the struct at ptr.
+func (m *fieldMap) callDefaults(ptr unsafe.Pointer) {
+	if m != nil && m.defaulter != nil {
+		reflect.NewAt(m.defaulter, ptr).Interface().(Defaulter).Defaults()
+	}
+}
+
+// setDefaults sets the default values given by the default tag options to
+// the fields of the struct at ptr which are absent from slots.
+func (m *fieldMap) setDefaults(dec *Decoder, ptr unsafe.Pointer, slots []*FieldAccessor) {
+	if m == nil || !m.check(dec) {
+		return
+	}
+next:
+	for i := range m.defaults {
+		field := &m.defaults[i]
+		for _, slot := range slots {
+			if slot != nil && slot.Alias == field.Alias {
+				continue next
+			}
+		}
+		field.setDefault(ptr)
+	}
+}
+
+// check reports whether the fields are valid, otherwise it sets their error
+// to dec.
+func (m *fieldMap) check(dec *Decoder) bool {
+	if m.err == nil {
+		return true
+	}
+	if dec.Error == nil {
+		dec.Error = m.err
+	}
+	return false
+}
+
+// markDefault marks the field named name as present in seen, which holds
+// the presence of the fields with default values.
+func (m *fieldMap) markDefault(seen []bool, name string) {
+	if field, ok := m.get(name); ok {
+		for i := range m.defaults {
+			if m.defaults[i].Alias == field.Alias {
+				seen[i] = true
+			}
+		}
+	}
+}
+
+// setAbsentDefaults sets the default values given by the default tag
+// options to the fields of the struct at ptr which are not marked in seen.
+func (m *fieldMap) setAbsentDefaults(dec *Decoder, ptr unsafe.Pointer, seen []bool) {
+	if !m.check(dec) {
+		return
+	}
+	for i := range seen {
+		if !seen[i] {
+			m.defaults[i].setDefault(ptr)
+		}
 	}
-	return fields
 }
 
-func getFields(t reflect.Type, tag ...string) []FieldAccessor {
-	return _getFields(reflect2.Type2(t).(reflect2.StructType), tag, map[string]bool{}, nil)
+func (m *fieldMap) get(name string) (field FieldAccessor, ok bool) {
+	if m == nil {
+		return
+	}
+	if field, ok = m.fields[name]; !ok && m.folded != nil {
+		field, ok = m.folded[strings.ToLower(name)]
+	}
+	return
 }
 
-var structFieldMapCache sync.Map
+func hasField(fields map[string]FieldAccessor, name string) bool {
+	_, ok := fields[name]
+	return ok
+}
 
-func getFieldMap(t reflect.Type) map[string]FieldAccessor {
-	if fieldMap, ok := structFieldMapCache.Load(t); ok {
-		return fieldMap.(map[string]FieldAccessor)
+func (c *Codec) getFieldMap(t reflect.Type) *fieldMap {
+	if m, ok := c.fieldMaps.Load(t); ok {
+		return m.(*fieldMap)
+	}
+	fields, extra := c.getFields(t)
+	m := &fieldMap{fields: make(map[string]FieldAccessor, len(fields)), extra: extra}
+	for _, field := range fields {
+		if field.err != nil && m.err == nil {
+			m.err = field.err
+		}
+		if field.def != nil {
+			var err error
+			if field.value, err = c.decodeDefault(&field); err != nil && m.err == nil {
+				m.err = err
+			}
+			m.defaults = append(m.defaults, field)
+		}
+		m.fields[field.Alias] = field
 	}
-	fields := getFields(t)
-	fieldMap := make(map[string]FieldAccessor, len(fields))
 	for _, field := range fields {
-		fieldMap[field.Alias] = field
+		for _, name := range field.names {
+			if !hasField(m.fields, name) {
+				m.fields[name] = field
+			}
+		}
 	}
-	structFieldMapCache.Store(t, fieldMap)
-	return fieldMap
+	if c.getFieldOptions(t).caseInsensitive {
+		m.folded = make(map[string]FieldAccessor, len(m.fields))
+		for _, field := range fields {
+			if name := strings.ToLower(field.Alias); !hasField(m.folded, name) {
+				m.folded[name] = field
+			}
+		}
+		for _, field := range fields {
+			for _, name := range field.names {
+				if name = strings.ToLower(name); !hasField(m.folded, name) {
+					m.folded[name] = field
+				}
+			}
+		}
+	}
+	if reflect.PtrTo(t).Implements(defaulterType) {
+		m.defaulter = t
+	}
+	c.fieldMaps.Store(t, m)
+	return m
+}
+
+// fieldSlots are the field accessors of struct type t for the field names of
+// a class in order, the slot of a field which t does not have is nil.
+type fieldSlots struct {
+	t     reflect.Type
+	slots []*FieldAccessor
+}
+
+func makeFieldSlots(names []string, fields *fieldMap) []*FieldAccessor {
+	slots := make([]*FieldAccessor, len(names))
+	for i, name := range names {
+		if field, ok := fields.get(name); ok {
+			slots[i] = &field
+		} else if fields != nil && fields.extra != nil {
+			slots[i] = extraSlot(fields.extra, name)
+		}
+	}
+	return slots
 }
 
 type structInfo struct {
 	name   string
 	names  []string
 	t      *reflect2.UnsafeStructType
-	fields map[string]FieldAccessor
+	fields *fieldMap
+	slots  []*FieldAccessor
+	other  *fieldSlots
 }
 
-func makeStructInfo(name string, names []string) (info structInfo) {
+func makeStructInfo(name string, names []string, registry *TypeRegistry, c *Codec) (info structInfo) {
 	info.name = name
 	info.names = names
-	if t := GetStructType(name); t != nil {
+	if t := registry.GetStructType(name); t != nil {
 		info.t = reflect2.Type2(t).(*reflect2.UnsafeStructType)
-		info.fields = getFieldMap(t)
+		info.fields = c.getFieldMap(t)
+		info.slots = makeFieldSlots(names, info.fields)
 	}
 	return
 }
@@ -158,38 +632,95 @@ func (dec *Decoder) ReadStruct() {
 		names[i] = dec.decodeString(stringType, dec.NextByte())
 	}
 	dec.Skip()
-	dec.ref = append(dec.ref, makeStructInfo(name, names))
+	dec.ref = append(dec.ref, makeStructInfo(name, names, dec.getRegistry(), dec.getCodec()))
+}
+
+// readClassIndex reads the class index of an object. If no class has been
+// defined at that index, for example when a stream encoded in session mode
+// is read by a fresh decoder, it reports an error, skips the object and
+// returns false.
+func (dec *Decoder) readClassIndex() (int, bool) {
+	index := dec.ReadInt()
+	if index >= 0 && index < len(dec.ref) {
+		return index, true
+	}
+	if dec.Error == nil {
+		dec.Error = DecodeError(fmt.Sprintf("hprose/encoding: undefined class index %d", index))
+	}
+	dec.AddReference(nil)
+	dec.Skip()
+	for tag := dec.NextByte(); tag != TagClosebrace && tag != 0; tag = dec.NextByte() {
+		dec.decodeInterface(interfaceType, tag)
+	}
+	return index, false
 }
 
 func (dec *Decoder) getStructInfo(index int) structInfo {
 	return dec.ref[index]
 }
 
-var structTypeMap sync.Map
-
-// Register the type of the proto with alias & tag.
-func Register(proto interface{}, alias string, tag ...string) {
-	t := reflect.TypeOf(proto)
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
-	}
-	if t.Kind() != reflect.Struct {
-		panic(fmt.Sprintf("hprose/encoding: invalid type: %s", t.String()))
+// getFieldSlots returns the field slots of struct type t for the class at
+// index, which are resolved once and reused for the following objects.
+func (dec *Decoder) getFieldSlots(index int, t reflect.Type) []*FieldAccessor {
+	info := &dec.ref[index]
+	if info.t != nil && info.t.Type1() == t {
+		return info.slots
 	}
-	structTypeMap.Store(alias, t)
-	name := t.Name()
-	if name == "" {
-		newAnonymousStructEncoder(t, tag...)
-	} else {
-		newStructEncoder(t, name, tag...)
+	if info.other == nil || info.other.t != t {
+		info.other = &fieldSlots{t, makeFieldSlots(info.names, dec.getCodec().getFieldMap(t))}
 	}
-	newStructDecoder(t)
+	return info.other.slots
+}
+
+// Register the type of the proto with alias & tag. The objects of this type
+// are encoded with alias as the class name. It returns an error if a default
+// value of its fields is invalid.
+func Register(proto interface{}, alias string, tag ...string) error {
+	return DefaultTypeRegistry.Register(proto, alias, tag...)
+}
+
+// RegisterWithNaming registers the type of the proto with alias & tag, the
+// names of its fields without alias tag are given by naming.
+func RegisterWithNaming(proto interface{}, alias string, naming FieldNaming, tag ...string) error {
+	return DefaultTypeRegistry.RegisterWithNaming(proto, alias, naming, tag...)
 }
 
 // GetStructType by alias
 func GetStructType(alias string) reflect.Type {
-	if t, ok := structTypeMap.Load(alias); ok {
-		return t.(reflect.Type)
+	return DefaultTypeRegistry.GetStructType(alias)
+}
+
+// fieldTable holds the immutable field table of a struct encoder or decoder.
+// The encoder or decoder is registered before its table is built, so that
+// self-referencing types can find it, and the table is published atomically
+// once it is complete. Readers load it without locking, and only wait while
+// it is still being built by another goroutine.
+type fieldTable struct {
+	value atomic.Value
+	ready chan struct{}
+}
+
+func newFieldTable() *fieldTable {
+	return &fieldTable{ready: make(chan struct{})}
+}
+
+// load returns the published table, or nil if building it failed.
+func (table *fieldTable) load() interface{} {
+	if v := table.value.Load(); v != nil {
+		return v
 	}
-	return nil
+	<-table.ready
+	return table.value.Load()
+}
+
+// build calls f and publishes its result. If f panics, unregister is called
+// so that the next lookup builds the table again.
+func (table *fieldTable) build(f func() interface{}, unregister func()) {
+	defer close(table.ready)
+	defer func() {
+		if table.value.Load() == nil {
+			unregister()
+		}
+	}()
+	table.value.Store(f())
 }
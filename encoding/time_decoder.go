@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/time_decoder.go                                 |
 |                                                          |
-| LastModified: Jun 27, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -163,6 +163,8 @@ func (dec *Decoder) decodeTime(t reflect.Type, tag byte) time.Time {
 			return dec.stringToTime(dec.ReadUnsafeString())
 		}
 		return dec.stringToTime(dec.ReadString())
+	case TagRef:
+		return readReference[time.Time](dec)
 	default:
 		dec.decodeError(t, tag)
 	}
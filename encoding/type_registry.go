@@ -0,0 +1,167 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/type_registry.go                                |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// NameStrategy returns the class name of an unregistered named struct type.
+type NameStrategy func(t reflect.Type) string
+
+// ShortName returns the type name without package, such as "User".
+func ShortName(t reflect.Type) string {
+	return t.Name()
+}
+
+// PackageName returns the type name qualified by the package path,
+// such as "github.com/example/model.User".
+func PackageName(t reflect.Type) string {
+	if pkg := t.PkgPath(); pkg != "" {
+		return pkg + "." + t.Name()
+	}
+	return t.Name()
+}
+
+var javaNameReplacer = strings.NewReplacer("/", ".", "-", "_")
+
+// JavaName returns the type name qualified by the package path in Java
+// style, such as "github.com.example.model.User".
+func JavaName(t reflect.Type) string {
+	if pkg := t.PkgPath(); pkg != "" {
+		return javaNameReplacer.Replace(pkg) + "." + t.Name()
+	}
+	return t.Name()
+}
+
+// TypeRegistry maps struct types to class names and back.
+type TypeRegistry struct {
+	strategy        NameStrategy
+	autoRegister    bool
+	naming          FieldNaming
+	caseInsensitive bool
+	types           sync.Map
+	names           sync.Map
+	// codec owns the struct encoders and decoders of the registered types,
+	// it is the Codec created by Config.Froze, DefaultCodec, or a Codec
+	// of the standalone registry itself.
+	codec *Codec
+}
+
+// NewTypeRegistry returns a TypeRegistry which names the unregistered
+// struct types with strategy. If autoRegister is true, a struct type is
+// registered by its name the first time it is encoded, so that the objects
+// of this type can be decoded back into it. The Encoders and Decoders with
+// this registry use the struct encoders and decoders of the types registered
+// to it, which do not change the ones of DefaultCodec.
+func NewTypeRegistry(strategy NameStrategy, autoRegister bool) *TypeRegistry {
+	if strategy == nil {
+		strategy = ShortName
+	}
+	r := &TypeRegistry{
+		strategy:     strategy,
+		autoRegister: autoRegister,
+	}
+	r.codec = &Codec{registry: r}
+	return r
+}
+
+// DefaultTypeRegistry is used by Encoder & Decoder without Registry.
+var DefaultTypeRegistry = NewTypeRegistry(ShortName, false)
+
+// FieldNaming sets the naming of the fields without alias tag of the struct
+// types registered in this registry afterwards. The default is LowerFirst.
+func (r *TypeRegistry) FieldNaming(naming FieldNaming) *TypeRegistry {
+	r.naming = naming
+	return r
+}
+
+// CaseInsensitive sets whether the fields of the struct types registered in
+// this registry afterwards are matched case-insensitively when decoding.
+func (r *TypeRegistry) CaseInsensitive(enable bool) *TypeRegistry {
+	r.caseInsensitive = enable
+	return r
+}
+
+// Register the type of the proto with alias & tag. The objects of this type
+// are encoded with alias as the class name. It returns an error if a default
+// value of its fields is invalid, the Decoders return it too when decoding
+// an object of this type.
+func (r *TypeRegistry) Register(proto interface{}, alias string, tag ...string) error {
+	return r.register(proto, alias, fieldOptions{r.naming, r.caseInsensitive}, tag)
+}
+
+// RegisterWithNaming registers the type of the proto with alias & tag, the
+// names of its fields without alias tag are given by naming instead of the
+// field naming of this registry.
+func (r *TypeRegistry) RegisterWithNaming(proto interface{}, alias string, naming FieldNaming, tag ...string) error {
+	return r.register(proto, alias, fieldOptions{naming, r.caseInsensitive}, tag)
+}
+
+func (r *TypeRegistry) register(proto interface{}, alias string, options fieldOptions, tag []string) error {
+	t := reflect.TypeOf(proto)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("hprose/encoding: invalid type: %s", t.String()))
+	}
+	r.types.Store(alias, t)
+	r.names.Store(t, alias)
+	c := r.codec
+	c.setFieldOptions(t, options)
+	name := t.Name()
+	if name == "" {
+		c.newAnonymousStructEncoder(t, tag...)
+	} else {
+		c.newStructEncoder(t, name, tag...)
+	}
+	if fields, ok := c.newStructDecoder(t).table.load().(*fieldMap); ok {
+		return fields.err
+	}
+	return nil
+}
+
+// GetStructType by alias.
+func (r *TypeRegistry) GetStructType(alias string) reflect.Type {
+	if t, ok := r.types.Load(alias); ok {
+		return t.(reflect.Type)
+	}
+	return nil
+}
+
+// GetName returns the class name of struct type t. If the registry
+// registers the types automatically, the name given by its strategy is
+// qualified by the package path when it is registered to another type
+// already, and GetName returns ClassNameError if the qualified one is
+// registered to another type too.
+func (r *TypeRegistry) GetName(t reflect.Type) (string, error) {
+	if name, ok := r.names.Load(t); ok {
+		return name.(string), nil
+	}
+	if !r.autoRegister {
+		return r.strategy(t), nil
+	}
+	names := [...]string{r.strategy(t), PackageName(t)}
+	for _, name := range names {
+		if other, loaded := r.types.LoadOrStore(name, t); !loaded || other == t {
+			r.names.Store(t, name)
+			return name, nil
+		}
+	}
+	return "", ClassNameError{t, names[1]}
+}
@@ -0,0 +1,128 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/type_registry_test.go                           |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestRegistryStruct struct {
+	A int
+}
+
+func TestNameStrategy(t *testing.T) {
+	st := reflect.TypeOf(TestRegistryStruct{})
+	assert.Equal(t, "TestRegistryStruct", ShortName(st))
+	assert.Equal(t, "github.com/hprose/hprose-golang/v3/encoding.TestRegistryStruct", PackageName(st))
+	assert.Equal(t, "github.com.hprose.hprose_golang.v3.encoding.TestRegistryStruct", JavaName(st))
+}
+
+func TestTypeRegistryEncode(t *testing.T) {
+	registry := NewTypeRegistry(JavaName, false)
+	registry.Register((*TestRegistryStruct)(nil), "com.example.Test")
+	enc := NewEncoder(nil)
+	enc.Registry = registry
+	enc.Encode(TestRegistryStruct{1})
+	assert.Equal(t, `c16"com.example.Test"1{s1"a"}o0{1}`, enc.String())
+	enc = NewEncoder(nil)
+	enc.Registry = NewTypeRegistry(PackageName, false)
+	enc.Encode(TestRegistryStruct{1})
+	assert.Equal(t, `c62"github.com/hprose/hprose-golang/v3/encoding.TestRegistryStruct"1{s1"a"}o0{1}`, enc.String())
+	enc = NewEncoder(nil)
+	enc.Encode(TestRegistryStruct{1})
+	assert.Equal(t, `c18"TestRegistryStruct"1{s1"a"}o0{1}`, enc.String())
+}
+
+func TestTypeRegistryDecode(t *testing.T) {
+	registry := NewTypeRegistry(PackageName, false)
+	registry.Register((*TestRegistryStruct)(nil), "Test")
+	dec := NewDecoder([]byte(`c4"Test"1{s1"a"}o0{1}`))
+	dec.Registry = registry
+	var v interface{}
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, &TestRegistryStruct{1}, v)
+	assert.Nil(t, DefaultTypeRegistry.GetStructType("Test"))
+	dec = NewDecoder([]byte(`c4"Test"1{s1"a"}o0{1}`))
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, map[string]interface{}{"a": 1}, v)
+}
+
+func TestTypeRegistryAutoRegister(t *testing.T) {
+	type TestStruct struct {
+		B string
+	}
+	registry := NewTypeRegistry(PackageName, true)
+	enc := NewEncoder(nil)
+	enc.Registry = registry
+	enc.Encode(TestStruct{"hello"})
+	name := "github.com/hprose/hprose-golang/v3/encoding.TestStruct"
+	assert.Equal(t, reflect.TypeOf(TestStruct{}), registry.GetStructType(name))
+	dec := NewDecoder(enc.Bytes())
+	dec.Registry = registry
+	var v interface{}
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, &TestStruct{"hello"}, v)
+}
+
+type TestStandaloneRegistryStruct struct {
+	UserID int
+}
+
+func TestTypeRegistryStandalone(t *testing.T) {
+	registry := NewTypeRegistry(ShortName, false)
+	registry.RegisterWithNaming((*TestStandaloneRegistryStruct)(nil), "User", SnakeCase)
+	enc := NewEncoder(nil)
+	enc.Registry = registry
+	enc.Encode(TestStandaloneRegistryStruct{1})
+	assert.Equal(t, `c4"User"1{s7"user_id"}o0{1}`, enc.String())
+	enc = NewEncoder(nil)
+	enc.Encode(TestStandaloneRegistryStruct{1})
+	assert.Equal(t, `c28"TestStandaloneRegistryStruct"1{s6"userID"}o0{1}`, enc.String())
+}
+
+func TestTypeRegistryAutoRegisterConflict(t *testing.T) {
+	registry := NewTypeRegistry(ShortName, true)
+	name, err := registry.GetName(reflect.TypeOf(TestRegistryStruct{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "TestRegistryStruct", name)
+	type TestRegistryStruct struct {
+		B string
+	}
+	st := reflect.TypeOf(TestRegistryStruct{})
+	qualified := "github.com/hprose/hprose-golang/v3/encoding.TestRegistryStruct"
+	name, err = registry.GetName(st)
+	assert.NoError(t, err)
+	assert.Equal(t, qualified, name)
+	assert.Equal(t, st, registry.GetStructType(qualified))
+	{
+		type TestRegistryStruct struct {
+			C string
+		}
+		ct := reflect.TypeOf(TestRegistryStruct{})
+		_, err = registry.GetName(ct)
+		assert.Equal(t, ClassNameError{ct, qualified}, err)
+		enc := NewEncoder(nil)
+		enc.Registry = registry
+		assert.NotPanics(t, func() {
+			enc.Encode(TestRegistryStruct{"c"})
+		})
+		assert.Equal(t, ClassNameError{ct, qualified}, enc.Error)
+	}
+}
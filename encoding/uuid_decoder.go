@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/uuid_decoder.go                                 |
 |                                                          |
-| LastModified: Jun 27, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -67,6 +67,8 @@ func (dec *Decoder) decodeUUID(t reflect.Type, tag byte) (id uuid.UUID) {
 			return dec.stringToUUID(dec.ReadUnsafeString())
 		}
 		return dec.stringToUUID(dec.ReadString())
+	case TagRef:
+		return readReference[uuid.UUID](dec)
 	default:
 		dec.decodeError(t, tag)
 	}
@@ -0,0 +1,436 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/value.go                                        |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind is the kind of hprose value a Value holds.
+type Kind uint8
+
+const (
+	// KindNull is the kind of null, it is the kind of the zero Value.
+	KindNull Kind = iota
+	// KindBool is the kind of true and false.
+	KindBool
+	// KindInt is the kind of integer and long.
+	KindInt
+	// KindFloat is the kind of double, NaN and infinity.
+	KindFloat
+	// KindString is the kind of empty, UTF8 char and string.
+	KindString
+	// KindBytes is the kind of bytes.
+	KindBytes
+	// KindTime is the kind of date and time.
+	KindTime
+	// KindUUID is the kind of guid.
+	KindUUID
+	// KindList is the kind of list.
+	KindList
+	// KindMap is the kind of map.
+	KindMap
+	// KindObject is the kind of object.
+	KindObject
+	// KindError is the kind of error.
+	KindError
+)
+
+var kindNames = [...]string{
+	KindNull:   "null",
+	KindBool:   "bool",
+	KindInt:    "int",
+	KindFloat:  "float",
+	KindString: "string",
+	KindBytes:  "bytes",
+	KindTime:   "time",
+	KindUUID:   "uuid",
+	KindList:   "list",
+	KindMap:    "map",
+	KindObject: "object",
+	KindError:  "error",
+}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "kind" + strconv.Itoa(int(k))
+}
+
+// Entry is a key-value pair of a map Value, or a field of an object Value
+// whose key is the string field name.
+type Entry struct {
+	Key   *Value
+	Value *Value
+}
+
+// Value is a hprose value of any kind, which is decoded from any stream
+// without losing anything and is encoded to the same stream again:
+//
+//   - the integers remember whether they are long, and the longs out of the
+//     int64 range are held as *big.Int,
+//   - the doubles, dates and times keep their text verbatim,
+//   - the strings of one char or read by reference keep their forms,
+//   - the map entries and object fields keep their order, and the objects
+//     keep their class names,
+//   - in reference mode, the references to the bytes, times, guids, lists,
+//     maps and objects share the referenced *Value, so the shared and cyclic
+//     values are rebuilt.
+//
+// The zero Value is null.
+type Value struct {
+	kind Kind
+	long bool
+	// tag is TagString for a decoded string of the full form, or TagRef for
+	// a string read by reference, which are written again in the same form.
+	tag byte
+	i   int64
+	f   float64
+	// s is the string, the class name of an object, the message of an error,
+	// or the text of a decoded double, date or time.
+	s string
+	// x is the *big.Int, []byte, time.Time, uuid.UUID, []*Value or []Entry.
+	x interface{}
+}
+
+// NewNull returns a null Value.
+func NewNull() *Value {
+	return &Value{}
+}
+
+// NewBool returns a bool Value.
+func NewBool(b bool) *Value {
+	v := &Value{kind: KindBool}
+	if b {
+		v.i = 1
+	}
+	return v
+}
+
+// NewInt returns an int Value.
+func NewInt(i int64) *Value {
+	return &Value{kind: KindInt, i: i}
+}
+
+// NewBigInt returns an int Value which is encoded as long.
+func NewBigInt(i *big.Int) *Value {
+	v := &Value{kind: KindInt, long: true}
+	if i.IsInt64() {
+		v.i = i.Int64()
+	} else {
+		v.x = new(big.Int).Set(i)
+	}
+	return v
+}
+
+// NewFloat returns a float Value.
+func NewFloat(f float64) *Value {
+	return &Value{kind: KindFloat, f: f}
+}
+
+// NewString returns a string Value.
+func NewString(s string) *Value {
+	return &Value{kind: KindString, s: s}
+}
+
+// NewError returns an error Value of message.
+func NewError(message string) *Value {
+	return &Value{kind: KindError, s: message}
+}
+
+// NewBytes returns a bytes Value.
+func NewBytes(b []byte) *Value {
+	return &Value{kind: KindBytes, x: b}
+}
+
+// NewTime returns a time Value.
+func NewTime(t time.Time) *Value {
+	return &Value{kind: KindTime, x: t}
+}
+
+// NewUUID returns a uuid Value.
+func NewUUID(id uuid.UUID) *Value {
+	return &Value{kind: KindUUID, x: id}
+}
+
+// NewList returns a list Value of items.
+func NewList(items ...*Value) *Value {
+	if items == nil {
+		items = []*Value{}
+	}
+	return &Value{kind: KindList, x: items}
+}
+
+// NewMap returns a map Value of entries in order.
+func NewMap(entries ...Entry) *Value {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	return &Value{kind: KindMap, x: entries}
+}
+
+// NewObject returns an object Value of class with fields in order, the keys
+// of fields must be string Values.
+func NewObject(class string, fields ...Entry) *Value {
+	if fields == nil {
+		fields = []Entry{}
+	}
+	return &Value{kind: KindObject, s: class, x: fields}
+}
+
+func (v *Value) mustBe(kind Kind, method string) {
+	if v.kind != kind {
+		panic("hprose/encoding: call of Value." + method + " on " + v.kind.String() + " Value")
+	}
+}
+
+// Kind returns the kind of v.
+func (v *Value) Kind() Kind {
+	return v.kind
+}
+
+// IsNull reports whether v is null.
+func (v *Value) IsNull() bool {
+	return v.kind == KindNull
+}
+
+// Bool returns the bool of v. It panics if the kind of v is not KindBool.
+func (v *Value) Bool() bool {
+	v.mustBe(KindBool, "Bool")
+	return v.i != 0
+}
+
+// IsLong reports whether the int v is encoded as long. It panics if the kind
+// of v is not KindInt.
+func (v *Value) IsLong() bool {
+	v.mustBe(KindInt, "IsLong")
+	return v.long
+}
+
+// Int returns the integer of v, the longs out of the int64 range are
+// truncated. It panics if the kind of v is not KindInt.
+func (v *Value) Int() int64 {
+	v.mustBe(KindInt, "Int")
+	if i, ok := v.x.(*big.Int); ok {
+		return i.Int64()
+	}
+	return v.i
+}
+
+// BigInt returns the integer of v as a new *big.Int. It panics if the kind
+// of v is not KindInt.
+func (v *Value) BigInt() *big.Int {
+	v.mustBe(KindInt, "BigInt")
+	if i, ok := v.x.(*big.Int); ok {
+		return new(big.Int).Set(i)
+	}
+	return big.NewInt(v.i)
+}
+
+// Float returns the number of v as float64. It panics if the kind of v is
+// neither KindFloat nor KindInt.
+func (v *Value) Float() float64 {
+	if v.kind == KindInt {
+		if i, ok := v.x.(*big.Int); ok {
+			f, _ := new(big.Float).SetInt(i).Float64()
+			return f
+		}
+		return float64(v.i)
+	}
+	v.mustBe(KindFloat, "Float")
+	return v.f
+}
+
+// String returns the string of v. Unlike the other accessors, it does not
+// panic for the other kinds, but returns a string of the form "<kind Value>".
+func (v *Value) String() string {
+	if v.kind != KindString {
+		return "<" + v.kind.String() + " Value>"
+	}
+	return v.s
+}
+
+// Bytes returns the bytes of v. It panics if the kind of v is not KindBytes.
+func (v *Value) Bytes() []byte {
+	v.mustBe(KindBytes, "Bytes")
+	return v.x.([]byte)
+}
+
+// Time returns the time of v. It panics if the kind of v is not KindTime.
+func (v *Value) Time() time.Time {
+	v.mustBe(KindTime, "Time")
+	return v.x.(time.Time)
+}
+
+// UUID returns the uuid of v. It panics if the kind of v is not KindUUID.
+func (v *Value) UUID() uuid.UUID {
+	v.mustBe(KindUUID, "UUID")
+	return v.x.(uuid.UUID)
+}
+
+// Class returns the class name of the object v. It panics if the kind of v
+// is not KindObject.
+func (v *Value) Class() string {
+	v.mustBe(KindObject, "Class")
+	return v.s
+}
+
+// Message returns the message of the error v. It panics if the kind of v is
+// not KindError.
+func (v *Value) Message() string {
+	v.mustBe(KindError, "Message")
+	return v.s
+}
+
+// Len returns the length of the string, bytes, list, map or object v. It
+// panics for the other kinds.
+func (v *Value) Len() int {
+	switch v.kind {
+	case KindString:
+		return len(v.s)
+	case KindBytes:
+		return len(v.x.([]byte))
+	case KindList:
+		return len(v.x.([]*Value))
+	case KindMap, KindObject:
+		return len(v.x.([]Entry))
+	}
+	panic("hprose/encoding: call of Value.Len on " + v.kind.String() + " Value")
+}
+
+// Index returns the i-th item of the list v. It panics if the kind of v is
+// not KindList, or i is out of range.
+func (v *Value) Index(i int) *Value {
+	v.mustBe(KindList, "Index")
+	return v.x.([]*Value)[i]
+}
+
+// Entries returns the entries of the map v or the fields of the object v in
+// order. It panics for the other kinds.
+func (v *Value) Entries() []Entry {
+	if v.kind != KindObject {
+		v.mustBe(KindMap, "Entries")
+	}
+	return v.x.([]Entry)
+}
+
+// matches reports whether v is the map key or field name of a path element.
+func (v *Value) matches(key interface{}) bool {
+	if v == nil {
+		return false
+	}
+	switch key := key.(type) {
+	case string:
+		return v.kind == KindString && v.s == key
+	case int:
+		return v.kind == KindInt && v.x == nil && v.i == int64(key)
+	}
+	return false
+}
+
+// find returns the position of the item or entry of the path element key in
+// v, or -1 if it is not found.
+func (v *Value) find(key interface{}) int {
+	switch v.kind {
+	case KindList:
+		if i, ok := key.(int); ok && i >= 0 && i < len(v.x.([]*Value)) {
+			return i
+		}
+	case KindMap, KindObject:
+		for i, entry := range v.x.([]Entry) {
+			if entry.Key.matches(key) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Get returns the value at path in v, or nil if there is no such value. Each
+// element of path is an int index of a list, or a string or int key of a map,
+// or a string field name of an object.
+func (v *Value) Get(path ...interface{}) *Value {
+	for _, key := range path {
+		if v == nil {
+			return nil
+		}
+		i := v.find(key)
+		switch {
+		case i < 0:
+			return nil
+		case v.kind == KindList:
+			v = v.x.([]*Value)[i]
+		default:
+			v = v.x.([]Entry)[i].Value
+		}
+	}
+	return v
+}
+
+// Set sets value at path in v. The last element of path may be the length
+// of a list to append value to it, or a new key of a map or a new field name
+// of an object to add value to it. It returns ErrInvalidPath if the parent of
+// path does not exist, or the last element does not fit the parent.
+func (v *Value) Set(value *Value, path ...interface{}) error {
+	n := len(path)
+	if n == 0 {
+		return ErrInvalidPath
+	}
+	if value == nil {
+		value = NewNull()
+	}
+	parent := v.Get(path[:n-1]...)
+	if parent == nil {
+		return ErrInvalidPath
+	}
+	key := path[n-1]
+	i := parent.find(key)
+	switch parent.kind {
+	case KindList:
+		items := parent.x.([]*Value)
+		switch {
+		case i >= 0:
+			items[i] = value
+		case key == len(items):
+			parent.x = append(items, value)
+		default:
+			return ErrInvalidPath
+		}
+	case KindMap, KindObject:
+		entries := parent.x.([]Entry)
+		if i >= 0 {
+			entries[i].Value = value
+			return nil
+		}
+		switch key := key.(type) {
+		case string:
+			parent.x = append(entries, Entry{NewString(key), value})
+		case int:
+			if parent.kind == KindObject {
+				return ErrInvalidPath
+			}
+			parent.x = append(entries, Entry{NewInt(int64(key)), value})
+		default:
+			return ErrInvalidPath
+		}
+	default:
+		return ErrInvalidPath
+	}
+	return nil
+}
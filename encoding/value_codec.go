@@ -0,0 +1,300 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/value_codec.go                                  |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modern-go/reflect2"
+)
+
+// referenceable reports whether v is written as a reference when it is
+// encoded again in reference mode. The strings are referenced by their
+// contents instead.
+func (v *Value) referenceable() bool {
+	switch v.kind {
+	case KindBytes, KindTime, KindUUID, KindList, KindMap, KindObject:
+		return true
+	}
+	return false
+}
+
+// referenced returns the bytes, time and uuid the decoder references by v,
+// or v itself.
+func (v *Value) referenced() interface{} {
+	switch v.kind {
+	case KindBytes, KindTime, KindUUID:
+		return v.x
+	}
+	return v
+}
+
+// valueOfReference returns the *Value of a referenced string, or ref itself.
+func valueOfReference(ref interface{}) interface{} {
+	switch ref := ref.(type) {
+	case string:
+		return &Value{kind: KindString, tag: TagRef, s: ref}
+	}
+	return ref
+}
+
+// valueEncoder is the implementation of ValueEncoder for Value/*Value.
+type valueEncoder struct{}
+
+func (valenc valueEncoder) Encode(enc *Encoder, v interface{}) {
+	if p := (*Value)(reflect2.PtrOf(v)); p == nil {
+		enc.WriteNil()
+	} else if p.referenceable() {
+		enc.EncodeReference(valenc, v)
+	} else {
+		valenc.Write(enc, v)
+	}
+	enc.autoFlush()
+}
+
+func (valenc valueEncoder) Write(enc *Encoder, v interface{}) {
+	p := (*Value)(reflect2.PtrOf(v))
+	switch p.kind {
+	case KindNull:
+		enc.WriteNil()
+	case KindBool:
+		enc.WriteBool(p.i != 0)
+	case KindInt:
+		switch {
+		case p.x != nil:
+			enc.WriteBigInt(p.x.(*big.Int))
+		case p.long:
+			enc.buf = append(enc.buf, TagLong)
+			enc.buf = strconv.AppendInt(enc.buf, p.i, 10)
+			enc.buf = append(enc.buf, TagSemicolon)
+		default:
+			enc.WriteInt64(p.i)
+		}
+	case KindFloat:
+		if p.s == "" {
+			enc.WriteFloat64(p.f)
+		} else {
+			enc.buf = append(enc.buf, TagDouble)
+			enc.buf = append(enc.buf, p.s...)
+			enc.buf = append(enc.buf, TagSemicolon)
+		}
+	case KindString:
+		switch p.tag {
+		case TagString:
+			enc.WriteString(p.s)
+		case TagRef:
+			if !enc.WriteStringReference(p.s) {
+				enc.WriteString(p.s)
+			}
+		default:
+			enc.EncodeString(p.s)
+		}
+	case KindError:
+		enc.AddReferenceCount(1)
+		enc.buf = append(enc.buf, TagError)
+		enc.buf = appendString(enc.buf, p.s, utf16Length(p.s))
+	case KindBytes:
+		enc.SetReference(v)
+		enc.buf = appendBytes(enc.buf, p.x.([]byte))
+	case KindTime:
+		enc.SetReference(v)
+		if p.s == "" {
+			enc.writeTime(p.x.(time.Time))
+		} else {
+			enc.buf = append(enc.buf, p.s...)
+		}
+	case KindUUID:
+		enc.SetReference(v)
+		enc.writeUUID(p.x.(uuid.UUID))
+	case KindList:
+		items := p.x.([]*Value)
+		enc.SetReference(v)
+		enc.WriteListHead(len(items))
+		for _, item := range items {
+			valenc.Encode(enc, item)
+		}
+		enc.WriteFoot()
+	case KindMap:
+		entries := p.x.([]Entry)
+		enc.SetReference(v)
+		enc.WriteMapHead(len(entries))
+		for _, entry := range entries {
+			valenc.Encode(enc, entry.Key)
+			valenc.Encode(enc, entry.Value)
+		}
+		enc.WriteFoot()
+	case KindObject:
+		fields := p.x.([]Entry)
+		r := writeClass(enc, p.s, fields, func(field *Entry) string {
+			return field.Key.s
+		})
+		enc.SetReference(v)
+		enc.WriteObjectHead(r)
+		for _, field := range fields {
+			valenc.Encode(enc, field.Value)
+		}
+		enc.WriteFoot()
+	}
+}
+
+// setLastReference replaces the value the decoder referenced last with v.
+func (dec *Decoder) setLastReference(v *Value) {
+	dec.SetReference(dec.LastReferenceIndex(), v)
+}
+
+func (dec *Decoder) decodeValue(tag byte) (v *Value) {
+	if tag == TagRef {
+		dec.decodeReference(&v)
+	}
+	if v == nil {
+		v = new(Value)
+		dec.readValue(v, tag)
+	}
+	return
+}
+
+// readValue reads the value of tag to v, an invalid reference is read as
+// null.
+func (dec *Decoder) readValue(v *Value, tag byte) {
+	*v = Value{}
+	if i := intDigits[tag]; i != invalidDigit {
+		v.kind, v.i = KindInt, int64(i)
+		return
+	}
+	switch tag {
+	case TagNull, TagRef:
+	case TagEmpty:
+		v.kind = KindString
+	case TagFalse:
+		v.kind = KindBool
+	case TagTrue:
+		v.kind, v.i = KindBool, 1
+	case TagInteger:
+		v.kind, v.i = KindInt, dec.ReadInt64()
+	case TagLong:
+		v.kind, v.long = KindInt, true
+		if i := dec.ReadBigInt(); i.IsInt64() {
+			v.i = i.Int64()
+		} else {
+			v.x = i
+		}
+	case TagDouble:
+		v.kind, v.s = KindFloat, string(dec.UnsafeUntil(TagSemicolon))
+		f, err := strconv.ParseFloat(v.s, 64)
+		if dec.Error == nil && err != nil {
+			dec.Error = err
+		}
+		v.f = f
+	case TagNaN:
+		v.kind, v.f = KindFloat, math.NaN()
+	case TagInfinity:
+		v.kind, v.f = KindFloat, math.Inf(1)
+		if dec.NextByte() == TagNeg {
+			v.f = math.Inf(-1)
+		}
+	case TagUTF8Char:
+		v.kind, v.s = KindString, dec.readSafeString(1)
+	case TagString:
+		v.kind, v.tag, v.s = KindString, TagString, dec.ReadString()
+	case TagError:
+		v.kind, v.s = KindError, dec.decodeString(stringType, dec.NextByte())
+	case TagBytes:
+		v.kind, v.x = KindBytes, dec.ReadBytes()
+		dec.setLastReference(v)
+	case TagTime:
+		v.kind = KindTime
+		v.x, v.s = dec.readTimeText(tag, dec.ReadTime)
+		dec.setLastReference(v)
+	case TagDate:
+		v.kind = KindTime
+		v.x, v.s = dec.readTimeText(tag, dec.ReadDateTime)
+		dec.setLastReference(v)
+	case TagGUID:
+		v.kind, v.x = KindUUID, dec.ReadUUID()
+		dec.setLastReference(v)
+	case TagList:
+		items := make([]*Value, dec.ReadInt())
+		v.kind, v.x = KindList, items
+		dec.AddReference(v)
+		for i := range items {
+			items[i] = dec.decodeValue(dec.NextByte())
+		}
+		dec.Skip()
+	case TagMap:
+		entries := make([]Entry, dec.ReadInt())
+		v.kind, v.x = KindMap, entries
+		dec.AddReference(v)
+		for i := range entries {
+			entries[i].Key = dec.decodeValue(dec.NextByte())
+			entries[i].Value = dec.decodeValue(dec.NextByte())
+		}
+		dec.Skip()
+	case TagObject:
+		if index, ok := dec.readClassIndex(); ok {
+			dec.readObjectValue(v, dec.getStructInfo(index))
+		}
+	case TagClass:
+		dec.ReadStruct()
+		dec.readValue(v, dec.NextByte())
+	default:
+		if dec.Error == nil {
+			dec.Error = DecodeError(fmt.Sprintf("hprose/encoding: invalid tag '%s'(0x%x)", string(tag), tag))
+		}
+	}
+}
+
+// readTimeText reads the time after tag with read, and returns it with the
+// text it is read from, which starts with tag.
+func (dec *Decoder) readTimeText(tag byte, read func() time.Time) (time.Time, string) {
+	r := dec.startRecording()
+	t := read()
+	dec.stopRecording(r)
+	return t, string(tag) + string(r.data)
+}
+
+func (dec *Decoder) readObjectValue(v *Value, structInfo structInfo) {
+	fields := make([]Entry, len(structInfo.names))
+	v.kind, v.s, v.x = KindObject, structInfo.name, fields
+	dec.AddReference(v)
+	for i, name := range structInfo.names {
+		fields[i] = Entry{NewString(name), dec.decodeValue(dec.NextByte())}
+	}
+	dec.Skip()
+}
+
+// valueDecoder is the implementation of ValueDecoder for Value.
+type valueDecoder struct{}
+
+func (valueDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	if tag == TagRef {
+		dec.decodeReference(p)
+	} else {
+		dec.readValue((*Value)(reflect2.PtrOf(p)), tag)
+	}
+}
+
+func (valueDecoder) Type() reflect.Type {
+	return valueType
+}
+
+func init() {
+	RegisterValueEncoder((*Value)(nil), valueEncoder{})
+	RegisterValueDecoder(valueDecoder{})
+}
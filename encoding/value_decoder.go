@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/value_decoder.go                                |
 |                                                          |
-| LastModified: Jun 21, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,73 +15,94 @@ package encoding
 
 import (
 	"reflect"
-	"sync"
 )
 
-var decoderMap sync.Map
-
 // ValueDecoder is the interface that groups the basic Decode methods.
 type ValueDecoder interface {
 	Decode(dec *Decoder, p interface{}, tag byte)
 	Type() reflect.Type
 }
 
-func getValueDecoder(t reflect.Type) (valdec ValueDecoder) {
-	if valdec, ok := decoderMap.Load(t); ok {
+// getValueDecoder returns the value decoder registered to the codec, or to
+// DefaultCodec, or the interface decoder, or the one built by the codec for
+// t. The built-in fast decoders registered to DefaultCodec are not used by
+// the custom codecs.
+func (c *Codec) getValueDecoder(t reflect.Type) ValueDecoder {
+	if valdec, ok := c.decoders.Load(t); ok {
+		return valdec.(ValueDecoder)
+	}
+	if c != DefaultCodec && !(c.isCustom() && isFastDecoder(t)) {
+		if valdec, ok := DefaultCodec.decoders.Load(t); ok {
+			return valdec.(ValueDecoder)
+		}
+	}
+	if valdec := c.interfaceDecoder(t); valdec != nil {
+		return valdec
+	}
+	if valdec, ok := c.valueDecoders.Load(t); ok {
 		return valdec.(ValueDecoder)
 	}
 	return nil
 }
 
+func (c *Codec) storeValueDecoder(valdec ValueDecoder) {
+	c.valueDecoders.Store(valdec.Type(), valdec)
+}
+
 // RegisterValueDecoder valdec
 func RegisterValueDecoder(valdec ValueDecoder) {
-	decoderMap.Store(valdec.Type(), valdec)
+	DefaultCodec.RegisterValueDecoder(valdec)
 }
 
 // GetValueDecoder of Type t
-func GetValueDecoder(t reflect.Type) (valdec ValueDecoder) {
-	valdec = getValueDecoder(t)
+func GetValueDecoder(t reflect.Type) ValueDecoder {
+	return DefaultCodec.GetValueDecoder(t)
+}
+
+// GetValueDecoder of Type t in the codec.
+func (c *Codec) GetValueDecoder(t reflect.Type) (valdec ValueDecoder) {
+	valdec = c.getValueDecoder(t)
 	if valdec == nil {
-		valdec = valueDecoderFactories[t.Kind()](t)
-		RegisterValueDecoder(valdec)
+		valdec = valueDecoderFactories[t.Kind()](c, t)
+		c.storeValueDecoder(valdec)
 	}
 	return
 }
 
-var valueDecoderFactories []func(t reflect.Type) ValueDecoder
+var valueDecoderFactories []func(c *Codec, t reflect.Type) ValueDecoder
 
-func invalidDecoder(t reflect.Type) ValueDecoder {
+func invalidDecoder(c *Codec, t reflect.Type) ValueDecoder {
 	panic(UnsupportedTypeError{t})
 }
 
 func init() {
-	valueDecoderFactories = []func(t reflect.Type) ValueDecoder{
+	valueDecoderFactories = []func(c *Codec, t reflect.Type) ValueDecoder{
 		reflect.Invalid:       invalidDecoder,
-		reflect.Bool:          func(t reflect.Type) ValueDecoder { return boolDecoder{t} },
-		reflect.Int:           func(t reflect.Type) ValueDecoder { return intDecoder{t} },
-		reflect.Int8:          func(t reflect.Type) ValueDecoder { return int8Decoder{t} },
-		reflect.Int16:         func(t reflect.Type) ValueDecoder { return int16Decoder{t} },
-		reflect.Int32:         func(t reflect.Type) ValueDecoder { return int32Decoder{t} },
-		reflect.Int64:         func(t reflect.Type) ValueDecoder { return int64Decoder{t} },
-		reflect.Uint:          func(t reflect.Type) ValueDecoder { return uintDecoder{t} },
-		reflect.Uint8:         func(t reflect.Type) ValueDecoder { return uint8Decoder{t} },
-		reflect.Uint16:        func(t reflect.Type) ValueDecoder { return uint16Decoder{t} },
-		reflect.Uint32:        func(t reflect.Type) ValueDecoder { return uint32Decoder{t} },
-		reflect.Uint64:        func(t reflect.Type) ValueDecoder { return uint64Decoder{t} },
-		reflect.Uintptr:       func(t reflect.Type) ValueDecoder { return uintptrDecoder{t} },
-		reflect.Float32:       func(t reflect.Type) ValueDecoder { return float32Decoder{t} },
-		reflect.Float64:       func(t reflect.Type) ValueDecoder { return float64Decoder{t} },
-		reflect.Complex64:     func(t reflect.Type) ValueDecoder { return complex64Decoder{t} },
-		reflect.Complex128:    func(t reflect.Type) ValueDecoder { return complex128Decoder{t} },
-		reflect.Array:         getArrayDecoder,
+		reflect.Bool:          func(c *Codec, t reflect.Type) ValueDecoder { return boolDecoder{t} },
+		reflect.Int:           func(c *Codec, t reflect.Type) ValueDecoder { return intDecoder{t} },
+		reflect.Int8:          func(c *Codec, t reflect.Type) ValueDecoder { return int8Decoder{t} },
+		reflect.Int16:         func(c *Codec, t reflect.Type) ValueDecoder { return int16Decoder{t} },
+		reflect.Int32:         func(c *Codec, t reflect.Type) ValueDecoder { return int32Decoder{t} },
+		reflect.Int64:         func(c *Codec, t reflect.Type) ValueDecoder { return int64Decoder{t} },
+		reflect.Uint:          func(c *Codec, t reflect.Type) ValueDecoder { return uintDecoder{t} },
+		reflect.Uint8:         func(c *Codec, t reflect.Type) ValueDecoder { return uint8Decoder{t} },
+		reflect.Uint16:        func(c *Codec, t reflect.Type) ValueDecoder { return uint16Decoder{t} },
+		reflect.Uint32:        func(c *Codec, t reflect.Type) ValueDecoder { return uint32Decoder{t} },
+		reflect.Uint64:        func(c *Codec, t reflect.Type) ValueDecoder { return uint64Decoder{t} },
+		reflect.Uintptr:       func(c *Codec, t reflect.Type) ValueDecoder { return uintptrDecoder{t} },
+		reflect.Float32:       func(c *Codec, t reflect.Type) ValueDecoder { return float32Decoder{t} },
+		reflect.Float64:       func(c *Codec, t reflect.Type) ValueDecoder { return float64Decoder{t} },
+		reflect.Complex64:     func(c *Codec, t reflect.Type) ValueDecoder { return complex64Decoder{t} },
+		reflect.Complex128:    func(c *Codec, t reflect.Type) ValueDecoder { return complex128Decoder{t} },
+		reflect.Array:         (*Codec).getArrayDecoder,
 		reflect.Chan:          invalidDecoder,
 		reflect.Func:          invalidDecoder,
-		reflect.Interface:     func(t reflect.Type) ValueDecoder { return interfaceDecoder{t} },
-		reflect.Map:           getMapDecoder,
-		reflect.Ptr:           getPtrDecoder,
-		reflect.Slice:         getSliceDecoder,
-		reflect.String:        func(t reflect.Type) ValueDecoder { return stringDecoder{t} },
-		reflect.Struct:        getStructDecoder,
+		reflect.Interface:     (*Codec).getInterfaceDecoder,
+		reflect.Map:           (*Codec).getMapDecoder,
+		reflect.Ptr:           (*Codec).getPtrDecoder,
+		reflect.Slice:         (*Codec).getSliceDecoder,
+		reflect.String:        func(c *Codec, t reflect.Type) ValueDecoder { return stringDecoder{t} },
+		reflect.Struct:        (*Codec).getStructDecoder,
 		reflect.UnsafePointer: invalidDecoder,
 	}
 }
@@ -6,7 +6,7 @@
 |                                                          |
 | encoding/value_encoder.go                                |
 |                                                          |
-| LastModified: Jun 25, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,7 +15,6 @@ package encoding
 
 import (
 	"reflect"
-	"sync"
 )
 
 // ValueEncoder is the interface that groups the basic Write and Encode methods.
@@ -24,33 +23,39 @@ type ValueEncoder interface {
 	Write(enc *Encoder, v interface{})
 }
 
-var structEncoderMap sync.Map
-var otherEncoderMap sync.Map
-
-func registerValueEncoder(t reflect.Type, valenc ValueEncoder) {
-	if t.Kind() == reflect.Struct {
-		structEncoderMap.Store(t, valenc)
-	} else {
-		otherEncoderMap.Store(t, valenc)
+func (c *Codec) getStructEncoder(t reflect.Type) ValueEncoder {
+	if valenc := c.registeredEncoder(t); valenc != nil {
+		return valenc
 	}
-}
-
-func getStructEncoder(t reflect.Type) ValueEncoder {
-	if valenc, ok := structEncoderMap.Load(t); ok {
+	if valenc, ok := c.structEncoders.Load(t); ok {
 		return valenc.(ValueEncoder)
 	}
 	name := t.Name()
 	if name == "" {
-		return newAnonymousStructEncoder(t)
+		return c.newAnonymousStructEncoder(t)
 	}
-	return newStructEncoder(t, name)
+	return c.newStructEncoder(t, name)
 }
 
-func getOtherEncoder(t reflect.Type) ValueEncoder {
-	if valenc, ok := otherEncoderMap.Load(t); ok {
+func (c *Codec) getOtherEncoder(t reflect.Type) ValueEncoder {
+	if t.Kind() == reflect.Struct {
+		return nil
+	}
+	return c.registeredEncoder(t)
+}
+
+// registeredEncoder returns the value encoder registered to the codec, or to
+// DefaultCodec if the codec has none for t, or the interface encoder of t.
+func (c *Codec) registeredEncoder(t reflect.Type) ValueEncoder {
+	if valenc, ok := c.encoders.Load(t); ok {
 		return valenc.(ValueEncoder)
 	}
-	return nil
+	if c != DefaultCodec {
+		if valenc, ok := DefaultCodec.encoders.Load(t); ok {
+			return valenc.(ValueEncoder)
+		}
+	}
+	return c.interfaceEncoder(t)
 }
 
 func checkType(v interface{}) reflect.Type {
@@ -63,14 +68,19 @@ func checkType(v interface{}) reflect.Type {
 
 // RegisterValueEncoder of type(v)
 func RegisterValueEncoder(v interface{}, valenc ValueEncoder) {
-	registerValueEncoder(checkType(v), valenc)
+	DefaultCodec.RegisterValueEncoder(v, valenc)
 }
 
 // GetValueEncoder of type(v)
 func GetValueEncoder(v interface{}) ValueEncoder {
+	return DefaultCodec.GetValueEncoder(v)
+}
+
+// GetValueEncoder of type(v) in the codec.
+func (c *Codec) GetValueEncoder(v interface{}) ValueEncoder {
 	t := checkType(v)
 	if t.Kind() == reflect.Struct {
-		return getStructEncoder(t)
+		return c.getStructEncoder(t)
 	}
-	return getOtherEncoder(t)
+	return c.getOtherEncoder(t)
 }
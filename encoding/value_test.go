@@ -0,0 +1,190 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| encoding/value_test.go                                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package encoding
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeValue(t *testing.T, data string, simple bool) *Value {
+	var v *Value
+	dec := NewDecoder([]byte(data)).Simple(simple)
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	return v
+}
+
+func encodeValue(t *testing.T, v *Value, simple bool) string {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(simple)
+	assert.NoError(t, enc.Encode(v))
+	return sb.String()
+}
+
+func TestValueRoundTrip(t *testing.T) {
+	id := uuid.MustParse("a8ac6f58-2dc4-4ba6-a6d6-8a3fa0f7a8b1")
+	data, err := Append(nil, []interface{}{
+		nil, true, false, 5, 123, int64(math.MaxInt64), new(big.Int).Lsh(big.NewInt(1), 70),
+		3.14, math.NaN(), math.Inf(-1), "", "x", "hello", []byte("bytes"),
+		time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC), id,
+		map[string]int{"a": 1}, &TestNode{Name: "node"}, Object{"Point", []Field{{"x", 1}, {"y", 2}}},
+	})
+	assert.NoError(t, err)
+	v := decodeValue(t, string(data), true)
+	assert.Equal(t, string(data), encodeValue(t, v, true))
+
+	for _, data := range []string{`l5;`, `d1.50;`, `m2{1u12u2}`, `a1{e}`,
+		`D19700101T000000Z`, `D19700101T000000.120000Z`, `T120000.100;`, `s1"x"`, `a2{s""e}`, `Es5"error"`,
+	} {
+		assert.Equal(t, data, encodeValue(t, decodeValue(t, data, true), true))
+	}
+	for _, data := range []string{
+		`a4{s1"x"r1;uxr1;}`,
+		`a3{D20261019;r1;s1"x"}`,
+		`a3{Es5"error"s5"error"r2;}`,
+	} {
+		assert.Equal(t, data, encodeValue(t, decodeValue(t, data, false), false))
+	}
+}
+
+func TestValueError(t *testing.T) {
+	v := decodeValue(t, `Es5"error"`, true)
+	assert.Equal(t, KindError, v.Kind())
+	assert.Equal(t, "error", v.Message())
+	assert.Equal(t, `a1{Es3"bad"}`, encodeValue(t, NewList(NewError("bad")), true))
+	assert.PanicsWithValue(t, "hprose/encoding: call of Value.Message on string Value", func() {
+		NewString("error").Message()
+	})
+	var s string
+	dec := NewDecoder([]byte(`Es5"error"`))
+	dec.Decode(&s)
+	assert.EqualError(t, dec.Error, "error")
+}
+
+func TestValueKinds(t *testing.T) {
+	v := decodeValue(t, `a9{nt5l99999999999999999999;d1.5;s5"hello"b2"hi"D20261019Zg{a8ac6f58-2dc4-4ba6-a6d6-8a3fa0f7a8b1}}`, true)
+	assert.Equal(t, KindList, v.Kind())
+	assert.Equal(t, 9, v.Len())
+	assert.True(t, v.Index(0).IsNull())
+	assert.True(t, v.Index(1).Bool())
+	assert.Equal(t, int64(5), v.Index(2).Int())
+	assert.False(t, v.Index(2).IsLong())
+	assert.True(t, v.Index(3).IsLong())
+	assert.Equal(t, "99999999999999999999", v.Index(3).BigInt().String())
+	assert.Equal(t, 1.5, v.Index(4).Float())
+	assert.Equal(t, 5.0, v.Index(2).Float())
+	assert.Equal(t, "hello", v.Index(5).String())
+	assert.Equal(t, []byte("hi"), v.Index(6).Bytes())
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), v.Index(7).Time())
+	assert.Equal(t, "a8ac6f58-2dc4-4ba6-a6d6-8a3fa0f7a8b1", v.Index(8).UUID().String())
+	assert.Equal(t, "<list Value>", v.String())
+	assert.Equal(t, "bool", KindBool.String())
+	assert.PanicsWithValue(t, "hprose/encoding: call of Value.Int on string Value", func() {
+		v.Index(5).Int()
+	})
+	assert.PanicsWithValue(t, "hprose/encoding: call of Value.Len on null Value", func() {
+		v.Index(0).Len()
+	})
+}
+
+func TestValueObject(t *testing.T) {
+	data := `a2{c5"Point"2{s1"x"s1"y"}o0{12}o0{34}}`
+	v := decodeValue(t, data, true)
+	p := v.Index(1)
+	assert.Equal(t, KindObject, p.Kind())
+	assert.Equal(t, "Point", p.Class())
+	assert.Equal(t, []Entry{{NewString("x"), NewInt(3)}, {NewString("y"), NewInt(4)}}, p.Entries())
+	assert.Equal(t, data, encodeValue(t, v, true))
+
+	v = NewList(NewObject("Point", Entry{NewString("x"), NewInt(1)}), NewObject("Point", Entry{NewString("x"), NewInt(2)}))
+	assert.Equal(t, `a2{c5"Point"1{s1"x"}o0{1}o0{2}}`, encodeValue(t, v, true))
+}
+
+func TestValueReferences(t *testing.T) {
+	data := `c8"TestNode"3{s4"name"s6"parent"s8"children"}o0{s4"root"na1{o0{uar3;n}}}`
+	root := decodeValue(t, data, false)
+	assert.Equal(t, "TestNode", root.Class())
+	assert.True(t, root == root.Get("children", 0, "parent"))
+	assert.Equal(t, data, encodeValue(t, root, false))
+
+	_, err := Append(nil, root)
+	assert.IsType(t, CycleError{}, err)
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	s := []int{1, 2}
+	data = string(encodeWithReference(t, []interface{}{"hello", "hello", &now, &now, &s, &s}))
+	v := decodeValue(t, data, false)
+	assert.True(t, v.Index(2) == v.Index(3))
+	assert.True(t, v.Index(4) == v.Index(5))
+	assert.Equal(t, "hello", v.Index(1).String())
+	assert.Equal(t, data, encodeValue(t, v, false))
+
+	var times []time.Time
+	dec := NewDecoder([]byte(`a2{D20261019Zr1;}`)).Simple(false)
+	dec.Decode(&times)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, times[0], times[1])
+	var mixed struct {
+		V Value
+		T time.Time
+	}
+	dec = NewDecoder([]byte(`m2{s1"v"D20261019Zs1"t"r2;}`)).Simple(false)
+	dec.Decode(&mixed)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, mixed.V.Time(), mixed.T)
+}
+
+func TestValuePath(t *testing.T) {
+	v := decodeValue(t, `m2{uaa2{1m1{ub2}}1s3"one"}`, true)
+	assert.Equal(t, int64(2), v.Get("a", 1, "b").Int())
+	assert.Equal(t, "one", v.Get(1).String())
+	assert.Nil(t, v.Get("a", 2))
+	assert.Nil(t, v.Get("x", 0))
+	assert.True(t, v == v.Get())
+
+	assert.NoError(t, v.Set(NewString("c"), "a", 1, "b"))
+	assert.NoError(t, v.Set(NewBool(true), "a", 2))
+	assert.NoError(t, v.Set(nil, "z"))
+	assert.Equal(t, `m3{uaa3{1m1{ubuc}t}1s3"one"uzn}`, encodeValue(t, v, true))
+
+	assert.Equal(t, ErrInvalidPath, v.Set(NewNull(), "a", 4))
+	assert.Equal(t, ErrInvalidPath, v.Set(NewNull(), "x", "y"))
+	assert.Equal(t, ErrInvalidPath, v.Set(NewNull(), 1, 0))
+	assert.Equal(t, ErrInvalidPath, v.Set(NewNull()))
+	assert.Equal(t, ErrInvalidPath, NewObject("Point").Set(NewNull(), 0))
+}
+
+func TestValueField(t *testing.T) {
+	type TestValueStruct struct {
+		Name  string
+		Extra *Value
+		Data  Value
+	}
+	var s TestValueStruct
+	dec := NewDecoder([]byte(`m3{s4"name"s3"abc"s5"extra"ns4"data"a1{1}}`))
+	dec.Decode(&s)
+	assert.NoError(t, dec.Error)
+	assert.Nil(t, s.Extra)
+	assert.Equal(t, int64(1), s.Data.Index(0).Int())
+	data, err := Append(nil, s)
+	assert.NoError(t, err)
+	assert.Equal(t, `c15"TestValueStruct"3{s4"name"s5"extra"s4"data"}o0{s3"abc"na1{1}}`, string(data))
+}
@@ -1,12 +1,18 @@
 module github.com/hprose/hprose-golang/v3
 
-go 1.11
+go 1.20
 
 require (
+	github.com/andot/complexconv v1.0.0
 	github.com/google/uuid v1.1.1
 	github.com/json-iterator/go v1.1.6
-	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.1
 	github.com/stretchr/testify v1.4.0
-	github.com/andot/complexconv v1.0.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
 )